@@ -0,0 +1,167 @@
+package databricks
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+// Values of the cluster_mode of a cluster.
+const (
+	clusterModeStandard        = "standard"
+	clusterModeSingleNode      = "single_node"
+	clusterModeHighConcurrency = "high_concurrency"
+)
+
+const sparkConfClusterProfile = "spark.databricks.cluster.profile"
+
+// clusterModeSparkConf is the spark configuration each cluster mode needs.
+var clusterModeSparkConf = map[string]map[string]string{
+	clusterModeSingleNode: {
+		sparkConfClusterProfile: "singleNode",
+		"spark.master":          "local[*]",
+	},
+	clusterModeHighConcurrency: {
+		sparkConfClusterProfile: "serverless",
+	},
+}
+
+// clusterModeDefaultSparkConf is the spark configuration added for each
+// cluster mode unless spark_conf sets it otherwise.
+var clusterModeDefaultSparkConf = map[string]map[string]string{
+	clusterModeHighConcurrency: {
+		"spark.databricks.repl.allowedLanguages": "sql,python,r",
+	},
+}
+
+// clusterModeAddedSparkConf returns the spark configuration, required or
+// default, that is added for mode.
+func clusterModeAddedSparkConf(mode string) map[string]string {
+	added := map[string]string{}
+	for key, val := range clusterModeDefaultSparkConf[mode] {
+		added[key] = val
+	}
+	for key, val := range clusterModeSparkConf[mode] {
+		added[key] = val
+	}
+	return added
+}
+
+// clusterModeTags are the tags each cluster mode needs.
+var clusterModeTags = map[string]map[string]string{
+	clusterModeSingleNode: {
+		"ResourceClass": "SingleNode",
+	},
+	clusterModeHighConcurrency: {
+		"ResourceClass": "Serverless",
+	},
+}
+
+// clusterModeFromSparkConf figures out the mode of a cluster from its spark
+// configuration.
+func clusterModeFromSparkConf(sparkConf map[string]string) string {
+	switch sparkConf[sparkConfClusterProfile] {
+	case clusterModeSparkConf[clusterModeSingleNode][sparkConfClusterProfile]:
+		return clusterModeSingleNode
+	case clusterModeSparkConf[clusterModeHighConcurrency][sparkConfClusterProfile]:
+		return clusterModeHighConcurrency
+	}
+	return clusterModeStandard
+}
+
+// getClusterSparkConf returns the configured spark configuration along with
+// the configuration required by the cluster mode and its defaults.
+func getClusterSparkConf(data *schema.ResourceData) (map[string]string, error) {
+	sparkConf := map[string]string{}
+	for key, val := range clusterModeDefaultSparkConf[data.Get("cluster_mode").(string)] {
+		sparkConf[key] = val
+	}
+	for key, val := range data.Get("spark_conf").(map[string]interface{}) {
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Spark configuration value %#v is not a string", val)
+		}
+		sparkConf[key] = valStr
+	}
+	for key, val := range clusterModeSparkConf[data.Get("cluster_mode").(string)] {
+		sparkConf[key] = val
+	}
+	return sparkConf, nil
+}
+
+// getClusterTags returns the configured tags along with the tags required
+// by the cluster mode and the default tags of the provider.
+func getClusterTags(
+	data *schema.ResourceData,
+	client interface{},
+) ([]db.ClusterTag, error) {
+	tags := map[string]interface{}{}
+	for key, val := range data.Get("tags").(map[string]interface{}) {
+		tags[key] = val
+	}
+	for key, val := range clusterModeTags[data.Get("cluster_mode").(string)] {
+		tags[key] = val
+	}
+	return mergeDefaultTags(client, tags)
+}
+
+// withoutClusterModeSettings removes the settings added for mode from
+// settings, unless they were configured as well.
+func withoutClusterModeSettings(
+	settings map[string]string,
+	modeSettings map[string]string,
+	configured map[string]interface{},
+) map[string]string {
+	filtered := map[string]string{}
+	for key, val := range settings {
+		_, isConfigured := configured[key]
+		if modeVal, ok := modeSettings[key]; ok && modeVal == val && !isConfigured {
+			continue
+		}
+		filtered[key] = val
+	}
+	return filtered
+}
+
+// validateClusterMode checks that the configuration of a cluster doesn't
+// conflict with its mode.
+func validateClusterMode(diff *schema.ResourceDiff) error {
+	mode := diff.Get("cluster_mode").(string)
+
+	if mode == clusterModeSingleNode && diff.Get("num_workers").(int) > 0 {
+		return fmt.Errorf("num_workers must be 0 when cluster_mode is %s", mode)
+	}
+
+	sparkConf := diff.Get("spark_conf").(map[string]interface{})
+	if _, ok := sparkConf[sparkConfClusterProfile]; ok {
+		return fmt.Errorf(
+			"spark_conf must not set %s, use cluster_mode instead",
+			sparkConfClusterProfile,
+		)
+	}
+	for key, val := range clusterModeSparkConf[mode] {
+		if configured, ok := sparkConf[key]; ok && configured != val {
+			return fmt.Errorf(
+				"spark_conf %s must be %q when cluster_mode is %s",
+				key,
+				val,
+				mode,
+			)
+		}
+	}
+
+	tags := diff.Get("tags").(map[string]interface{})
+	for key, val := range clusterModeTags[mode] {
+		if configured, ok := tags[key]; ok && configured != val {
+			return fmt.Errorf(
+				"tag %s must be %q when cluster_mode is %s",
+				key,
+				val,
+				mode,
+			)
+		}
+	}
+
+	return nil
+}
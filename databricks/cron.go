@@ -0,0 +1,393 @@
+package databricks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quartzCron is a parsed Quartz cron expression, see
+// http://www.quartz-scheduler.org/documentation/quartz-2.3.0/tutorials/crontrigger.html
+type quartzCron struct {
+	seconds map[int]bool
+	minutes map[int]bool
+	hours   map[int]bool
+	months  map[int]bool
+	// years is nil when the year field is omitted
+	years map[int]bool
+
+	// day of month, unused when anyDayOfMonth is set
+	anyDayOfMonth  bool
+	daysOfMonth    map[int]bool
+	lastDayOfMonth bool
+	lastDayOffset  int
+	// nearestWeekday is the day of month of a W expression, or 0
+	nearestWeekday int
+	lastWeekday    bool
+
+	// day of week (1 = SUN, 7 = SAT), unused when anyDayOfWeek is set
+	anyDayOfWeek bool
+	daysOfWeek   map[int]bool
+	// lastDayOfWeek is the day of an L expression, e.g. 6L, or 0
+	lastDayOfWeek int
+	// nthDayOfWeek and nthWeek are the day and week of a # expression, e.g.
+	// 6#3, or 0
+	nthDayOfWeek int
+	nthWeek      int
+}
+
+// cronField describes the values a field of a cron expression can take.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var (
+	cronSecondsField    = cronField{name: "seconds", min: 0, max: 59}
+	cronMinutesField    = cronField{name: "minutes", min: 0, max: 59}
+	cronHoursField      = cronField{name: "hours", min: 0, max: 23}
+	cronDayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	cronMonthField      = cronField{
+		name:  "month",
+		min:   1,
+		max:   12,
+		names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"},
+	}
+	cronDayOfWeekField = cronField{
+		name:  "day of week",
+		min:   1,
+		max:   7,
+		names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"},
+	}
+	cronYearField = cronField{name: "year", min: 1970, max: 2099}
+)
+
+// parseQuartzCron parses a Quartz cron expression made of seconds, minutes,
+// hours, day of month, month, day of week and an optional year.
+func parseQuartzCron(expr string) (*quartzCron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf(
+			"cron expression must have 6 or 7 fields, got %d",
+			len(fields),
+		)
+	}
+
+	var err error
+	cron := &quartzCron{}
+	if cron.seconds, err = cronSecondsField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if cron.minutes, err = cronMinutesField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if cron.hours, err = cronHoursField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if err = cron.parseDayOfMonth(fields[3]); err != nil {
+		return nil, err
+	}
+	if cron.months, err = cronMonthField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if err = cron.parseDayOfWeek(fields[5]); err != nil {
+		return nil, err
+	}
+	if len(fields) == 7 {
+		if cron.years, err = cronYearField.parse(fields[6]); err != nil {
+			return nil, err
+		}
+	}
+
+	if cron.anyDayOfMonth == cron.anyDayOfWeek {
+		return nil, fmt.Errorf(
+			"exactly one of day of month and day of week must be '?'",
+		)
+	}
+
+	return cron, nil
+}
+
+func (c *quartzCron) parseDayOfMonth(expr string) error {
+	switch {
+	case expr == "?":
+		c.anyDayOfMonth = true
+		return nil
+	case expr == "LW":
+		c.lastWeekday = true
+		return nil
+	case strings.HasPrefix(expr, "L"):
+		c.lastDayOfMonth = true
+		if len(expr) == 1 {
+			return nil
+		}
+		if !strings.HasPrefix(expr, "L-") {
+			return fmt.Errorf("invalid day of month %q", expr)
+		}
+		offset, err := strconv.Atoi(expr[2:])
+		if err != nil || offset < 0 || offset > 30 {
+			return fmt.Errorf("invalid day of month offset %q", expr)
+		}
+		c.lastDayOffset = offset
+		return nil
+	case strings.HasSuffix(expr, "W"):
+		day, err := cronDayOfMonthField.parseValue(strings.TrimSuffix(expr, "W"))
+		if err != nil {
+			return err
+		}
+		c.nearestWeekday = day
+		return nil
+	}
+
+	days, err := cronDayOfMonthField.parse(expr)
+	if err != nil {
+		return err
+	}
+	c.daysOfMonth = days
+	return nil
+}
+
+func (c *quartzCron) parseDayOfWeek(expr string) error {
+	switch {
+	case expr == "?":
+		c.anyDayOfWeek = true
+		return nil
+	case expr == "L":
+		// L on its own is the last day of the week, saturday
+		c.daysOfWeek = map[int]bool{7: true}
+		return nil
+	case strings.HasSuffix(expr, "L"):
+		day, err := cronDayOfWeekField.parseValue(strings.TrimSuffix(expr, "L"))
+		if err != nil {
+			return err
+		}
+		c.lastDayOfWeek = day
+		return nil
+	case strings.Contains(expr, "#"):
+		parts := strings.SplitN(expr, "#", 2)
+		day, err := cronDayOfWeekField.parseValue(parts[0])
+		if err != nil {
+			return err
+		}
+		week, err := strconv.Atoi(parts[1])
+		if err != nil || week < 1 || week > 5 {
+			return fmt.Errorf("invalid week %q in day of week %q", parts[1], expr)
+		}
+		c.nthDayOfWeek = day
+		c.nthWeek = week
+		return nil
+	}
+
+	days, err := cronDayOfWeekField.parse(expr)
+	if err != nil {
+		return err
+	}
+	c.daysOfWeek = days
+	return nil
+}
+
+// parse parses a comma separated list of values, ranges and increments.
+func (f cronField) parse(expr string) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangeExpr = item[:i]
+			var err error
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid increment in %s %q", f.name, item)
+			}
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = f.parseValue(bounds[0]); err != nil {
+				return nil, err
+			}
+			if end, err = f.parseValue(bounds[1]); err != nil {
+				return nil, err
+			}
+		default:
+			var err error
+			if start, err = f.parseValue(rangeExpr); err != nil {
+				return nil, err
+			}
+			// a single value with an increment runs until the max
+			if step == 1 {
+				end = start
+			}
+		}
+
+		if start > end {
+			// ranges may wrap around, e.g. FRI-MON or 22-2
+			for v := start; v <= f.max; v += step {
+				values[v] = true
+			}
+			for v := f.min + (step-(f.max-start+1)%step)%step; v <= end; v += step {
+				values[v] = true
+			}
+			continue
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// parseValue parses a single number or name of the field.
+func (f cronField) parseValue(expr string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(expr, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, expr)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf(
+			"%s %d is out of range %d-%d",
+			f.name,
+			v,
+			f.min,
+			f.max,
+		)
+	}
+	return v, nil
+}
+
+// next returns the first time after t the cron expression fires in the
+// location of t, or false if it never fires again.
+func (c *quartzCron) next(t time.Time) (time.Time, bool) {
+	hours := sortedCronValues(c.hours)
+	minutes := sortedCronValues(c.minutes)
+	seconds := sortedCronValues(c.seconds)
+
+	loc := t.Location()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	for ; day.Year() <= cronYearField.max; day = day.AddDate(0, 0, 1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				for _, second := range seconds {
+					fire := time.Date(
+						day.Year(),
+						day.Month(),
+						day.Day(),
+						hour,
+						minute,
+						second,
+						0,
+						loc,
+					)
+					if fire.After(t) {
+						return fire, true
+					}
+				}
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (c *quartzCron) matchesDay(day time.Time) bool {
+	if c.years != nil && !c.years[day.Year()] {
+		return false
+	}
+	if !c.months[int(day.Month())] {
+		return false
+	}
+	if c.anyDayOfMonth {
+		return c.matchesDayOfWeek(day)
+	}
+	return c.matchesDayOfMonth(day)
+}
+
+func (c *quartzCron) matchesDayOfMonth(day time.Time) bool {
+	lastDay := daysInMonth(day)
+	switch {
+	case c.lastWeekday:
+		return day.Day() == nearestWeekday(day, lastDay)
+	case c.lastDayOfMonth:
+		return day.Day() == lastDay-c.lastDayOffset
+	case c.nearestWeekday > 0:
+		target := c.nearestWeekday
+		if target > lastDay {
+			return false
+		}
+		return day.Day() == nearestWeekday(day, target)
+	}
+	return c.daysOfMonth[day.Day()]
+}
+
+func (c *quartzCron) matchesDayOfWeek(day time.Time) bool {
+	// quartz counts days of the week from 1 = SUN
+	weekday := int(day.Weekday()) + 1
+	switch {
+	case c.lastDayOfWeek > 0:
+		return weekday == c.lastDayOfWeek && day.Day()+7 > daysInMonth(day)
+	case c.nthDayOfWeek > 0:
+		return weekday == c.nthDayOfWeek && (day.Day()-1)/7+1 == c.nthWeek
+	}
+	return c.daysOfWeek[weekday]
+}
+
+// nearestWeekday returns the weekday closest to the target day of the month
+// of day, without leaving the month.
+func nearestWeekday(day time.Time, target int) int {
+	lastDay := daysInMonth(day)
+	weekday := time.Date(
+		day.Year(),
+		day.Month(),
+		target,
+		0, 0, 0, 0,
+		day.Location(),
+	).Weekday()
+	switch weekday {
+	case time.Saturday:
+		if target == 1 {
+			return target + 2
+		}
+		return target - 1
+	case time.Sunday:
+		if target == lastDay {
+			return target - 2
+		}
+		return target + 1
+	}
+	return target
+}
+
+func daysInMonth(day time.Time) int {
+	return time.Date(
+		day.Year(),
+		day.Month()+1,
+		0, 0, 0, 0, 0,
+		day.Location(),
+	).Day()
+}
+
+func sortedCronValues(values map[int]bool) []int {
+	sorted := make([]int, 0, len(values))
+	for v := range values {
+		sorted = append(sorted, v)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
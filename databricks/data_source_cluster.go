@@ -0,0 +1,210 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+func dataSourceCluster() *schema.Resource {
+	clusterSchema := clusterDataSchema()
+	clusterSchema["cluster_id"].Optional = true
+	clusterSchema["cluster_id"].ConflictsWith = []string{"cluster_name"}
+	clusterSchema["cluster_name"].Optional = true
+	clusterSchema["cluster_name"].ConflictsWith = []string{"cluster_id"}
+
+	return &schema.Resource{
+		Read:   dataSourceClusterRead,
+		Schema: clusterSchema,
+	}
+}
+
+func dataSourceClusterRead(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+	clusterID := data.Get("cluster_id").(string)
+	clusterName := data.Get("cluster_name").(string)
+
+	var cluster *db.ClusterInfo
+	switch {
+	case len(clusterID) > 0:
+		getRes, err := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+		if err != nil {
+			return err
+		}
+		cluster = getRes
+	case len(clusterName) > 0:
+		clusters, err := client.(*providerMeta).client.Cluster().List(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range clusters {
+			if clusters[i].ClusterName != clusterName {
+				continue
+			}
+			if cluster != nil {
+				return fmt.Errorf(
+					"more than one cluster is named %q", clusterName,
+				)
+			}
+			cluster = &clusters[i]
+		}
+		if cluster == nil {
+			return fmt.Errorf("no cluster is named %q", clusterName)
+		}
+	default:
+		return fmt.Errorf("one of cluster_id or cluster_name must be set")
+	}
+
+	data.SetId(cluster.ClusterID)
+	for key, val := range flattenCluster(cluster) {
+		if err := data.Set(key, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clusterDataSchema is the schema of the databricks_cluster resource with
+// every attribute computed, along with the attributes that are only known
+// once a cluster exists.
+func clusterDataSchema() map[string]*schema.Schema {
+	clusterSchema := computedSchema(resourceCluster().Schema)
+	// only used when changing a cluster
+	delete(clusterSchema, "restart_policy")
+	delete(clusterSchema, "edit_pending")
+	clusterSchema["cluster_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: `Canonical identifier for the cluster.`,
+		Computed:    true,
+	}
+	clusterSchema["state"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: `Current state of the cluster.`,
+		Computed:    true,
+	}
+	clusterSchema["creator"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: `Creator user name of the cluster.`,
+		Computed:    true,
+	}
+	return clusterSchema
+}
+
+// computedSchema copies a resource schema, turning every attribute into a
+// computed one so it can be used by a data source.
+func computedSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	computed := map[string]*schema.Schema{}
+	for key, val := range s {
+		attr := &schema.Schema{
+			Type:        val.Type,
+			Description: val.Description,
+			Sensitive:   val.Sensitive,
+			Computed:    true,
+			Elem:        val.Elem,
+		}
+		if elem, ok := val.Elem.(*schema.Resource); ok {
+			attr.Elem = &schema.Resource{
+				Schema: computedSchema(elem.Schema),
+			}
+		}
+		computed[key] = attr
+	}
+	return computed
+}
+
+// flattenCluster converts a cluster into the attributes of the cluster data
+// sources.
+func flattenCluster(cluster *db.ClusterInfo) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"cluster_id":              cluster.ClusterID,
+		"cluster_name":            cluster.ClusterName,
+		"spark_version":           cluster.SparkVersion,
+		"ssh_keys":                cluster.SSHPublicKeys,
+		"node_type":               cluster.NodeTypeID,
+		"driver_node_type":        cluster.DriverNodeTypeID,
+		"instance_pool_id":        cluster.InstancePoolID,
+		"driver_instance_pool_id": cluster.DriverInstancePoolID,
+		"policy_id":               cluster.PolicyID,
+		"autotermination_minutes": int(cluster.AutoterminationMinutes),
+		"enable_elastic_disk":     cluster.EnableElasticDisk,
+		"is_pinned":               len(cluster.PinnedByUserName) > 0,
+		"spark_env":               cluster.SparkEnvVars,
+		"spark_conf":              cluster.SparkConf,
+		"cluster_mode":            clusterModeFromSparkConf(cluster.SparkConf),
+		"state":                   string(cluster.State),
+		"creator":                 cluster.CreatorUserName,
+	}
+
+	if cluster.NumWorkers != nil {
+		attrs["num_workers"] = int(*cluster.NumWorkers)
+	}
+	if cluster.Autoscale != nil {
+		attrs["min_workers"] = int(cluster.Autoscale.Min)
+		attrs["max_workers"] = int(cluster.Autoscale.Max)
+	}
+
+	tags := map[string]string{}
+	for _, tag := range cluster.CustomTags {
+		tags[tag.Key] = tag.Value
+	}
+	attrs["tags"] = tags
+
+	if cluster.DockerImage != nil {
+		image := map[string]interface{}{
+			"url": cluster.DockerImage.URL,
+		}
+		if cluster.DockerImage.BasicAuth != nil {
+			image["basic_auth"] = []interface{}{
+				map[string]interface{}{
+					"username": cluster.DockerImage.BasicAuth.Username,
+				},
+			}
+		}
+		attrs["docker_image"] = []interface{}{image}
+	}
+
+	if awsAttrs := cluster.AWSAttributes; awsAttrs != nil {
+		attrsMap := map[string]interface{}{
+			"first_on_demand": int(awsAttrs.FirstOnDemand),
+			"availability":    string(awsAttrs.Availability),
+			"zone_id":         awsAttrs.ZoneID,
+		}
+		if awsAttrs.InstanceProfileARN != nil {
+			attrsMap["instance_profile_arn"] = *awsAttrs.InstanceProfileARN
+		}
+		if awsAttrs.SpotBidPricePercent != nil {
+			attrsMap["spot_bid_price_percent"] = int(*awsAttrs.SpotBidPricePercent)
+		}
+		if awsAttrs.EBSVolumeCount != nil {
+			attrsMap["ebs_volume_count"] = int(*awsAttrs.EBSVolumeCount)
+		}
+		if awsAttrs.EBSVolumeSize != nil {
+			attrsMap["ebs_volume_size"] = int(*awsAttrs.EBSVolumeSize)
+		}
+		attrs["aws_attributes"] = []interface{}{attrsMap}
+	}
+	if azureAttrs := cluster.AzureAttributes; azureAttrs != nil {
+		attrs["azure_attributes"] = []interface{}{
+			map[string]interface{}{
+				"first_on_demand":    int(azureAttrs.FirstOnDemand),
+				"availability":       string(azureAttrs.Availability),
+				"spot_bid_max_price": azureAttrs.SpotBidMaxPrice,
+			},
+		}
+	}
+	if gcpAttrs := cluster.GCPAttributes; gcpAttrs != nil {
+		attrs["gcp_attributes"] = []interface{}{
+			map[string]interface{}{
+				"google_service_account": gcpAttrs.GoogleServiceAccount,
+				"availability":           string(gcpAttrs.Availability),
+				"boot_disk_size":         int(gcpAttrs.BootDiskSize),
+				"zone_id":                gcpAttrs.ZoneID,
+			},
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,149 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+	"github.com/mitchellh/hashstructure"
+)
+
+func dataSourceClusterEvents() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceClusterEventsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `The ID of the cluster to retrieve events about.`,
+				Required:    true,
+			},
+			"start_time": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `The start time in RFC3339 format. If empty,
+				returns events starting from the beginning of time.`,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			"end_time": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `The end time in RFC3339 format. If empty, returns
+				events up to the current time.`,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			"event_types": &schema.Schema{
+				Type: schema.TypeList,
+				Description: `An optional set of event types to filter on, e.g.
+				TERMINATING or DRIVER_HEALTHY. If empty, all event types are
+				returned.`,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"limit": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `The maximum number of events to return.`,
+				Optional:    true,
+				Default:     50,
+			},
+			"events": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `The events, most recent first.`,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"timestamp": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"user": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cause": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason_code": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason_parameters": &schema.Schema{
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClusterEventsRead(data *schema.ResourceData, client interface{}) error {
+	eventsReq := &db.ClusterEventsRequest{
+		ClusterID: data.Get("cluster_id").(string),
+		Order:     db.ListOrderDesc,
+		Limit:     int64(data.Get("limit").(int)),
+	}
+
+	if startTime, ok := data.GetOk("start_time"); ok {
+		t, err := time.Parse(time.RFC3339, startTime.(string))
+		if err != nil {
+			return err
+		}
+		eventsReq.StartTime = t.UnixNano() / int64(time.Millisecond)
+	}
+	if endTime, ok := data.GetOk("end_time"); ok {
+		t, err := time.Parse(time.RFC3339, endTime.(string))
+		if err != nil {
+			return err
+		}
+		eventsReq.EndTime = t.UnixNano() / int64(time.Millisecond)
+	}
+	for _, eventType := range data.Get("event_types").([]interface{}) {
+		eventsReq.EventTypes = append(
+			eventsReq.EventTypes,
+			db.ClusterEventType(eventType.(string)),
+		)
+	}
+
+	events, err := client.(*providerMeta).client.Cluster().Events(
+		context.Background(),
+		eventsReq,
+	)
+	if err != nil {
+		return err
+	}
+
+	eventsData := make([]interface{}, len(events))
+	for i, event := range events {
+		eventData := map[string]interface{}{
+			"timestamp": time.Unix(0, event.Timestamp*int64(time.Millisecond)).Format(time.RFC3339),
+			"type":      string(event.Type),
+		}
+		if event.Details != nil {
+			eventData["user"] = event.Details.User
+			eventData["cause"] = event.Details.Cause
+			if event.Details.Reason != nil {
+				eventData["reason_code"] = event.Details.Reason.Code
+				eventData["reason_parameters"] = event.Details.Reason.Parameters
+			}
+		}
+		eventsData[i] = eventData
+	}
+
+	id, err := hashstructure.Hash(eventsReq, nil)
+	if err != nil {
+		return err
+	}
+	data.SetId(fmt.Sprintf("%d", id))
+
+	return data.Set("events", eventsData)
+}
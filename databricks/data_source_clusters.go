@@ -0,0 +1,113 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+	"github.com/mitchellh/hashstructure"
+)
+
+func dataSourceClusters() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceClustersRead,
+		Schema: map[string]*schema.Schema{
+			"name_contains": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Only list clusters whose name contains this.`,
+				Optional:    true,
+			},
+			"tags": &schema.Schema{
+				Type:        schema.TypeMap,
+				Description: `Only list clusters with all of these tags.`,
+				Optional:    true,
+			},
+			"state": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Only list clusters in this state, e.g. RUNNING
+				or TERMINATED.`,
+				Optional: true,
+			},
+			"creator": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Only list clusters created by this user.`,
+				Optional:    true,
+			},
+			"ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `Ids of the matching clusters.`,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"clusters": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `The matching clusters.`,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: clusterDataSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClustersRead(data *schema.ResourceData, client interface{}) error {
+	clusters, err := client.(*providerMeta).client.Cluster().List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	nameContains := data.Get("name_contains").(string)
+	tags := data.Get("tags").(map[string]interface{})
+	state := data.Get("state").(string)
+	creator := data.Get("creator").(string)
+
+	ids := []string{}
+	matches := []interface{}{}
+	for i := range clusters {
+		cluster := &clusters[i]
+		if !strings.Contains(cluster.ClusterName, nameContains) {
+			continue
+		}
+		if len(state) > 0 && !strings.EqualFold(string(cluster.State), state) {
+			continue
+		}
+		if len(creator) > 0 && cluster.CreatorUserName != creator {
+			continue
+		}
+		if !clusterHasTags(cluster, tags) {
+			continue
+		}
+		ids = append(ids, cluster.ClusterID)
+		matches = append(matches, flattenCluster(cluster))
+	}
+
+	id, err := hashstructure.Hash(ids, nil)
+	if err != nil {
+		return err
+	}
+	data.SetId(fmt.Sprintf("%d", id))
+	data.Set("ids", ids)
+
+	return data.Set("clusters", matches)
+}
+
+func clusterHasTags(cluster *db.ClusterInfo, tags map[string]interface{}) bool {
+	for key, val := range tags {
+		found := false
+		for _, tag := range cluster.CustomTags {
+			if tag.Key == key && tag.Value == val.(string) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+	"github.com/mitchellh/hashstructure"
+)
+
+func dataSourceJobRuns() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceJobRunsRead,
+		Schema: map[string]*schema.Schema{
+			"job_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `The ID of the job to list the runs of.`,
+				Required:    true,
+			},
+			"active_only": &schema.Schema{
+				Type:          schema.TypeBool,
+				Description:   `Only list runs that are pending or running.`,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"completed_only"},
+			},
+			"completed_only": &schema.Schema{
+				Type:          schema.TypeBool,
+				Description:   `Only list runs that have finished.`,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"active_only"},
+			},
+			"limit": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `The maximum number of runs to return, between 1 and 1000.`,
+				Optional:    true,
+				Default:     20,
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					if limit := i.(int); limit < 1 || limit > 1000 {
+						return []string{}, []error{fmt.Errorf(
+							"%s must be between 1 and 1000, got %d", s, limit),
+						}
+					}
+					return []string{}, []error{}
+				},
+			},
+			"offset": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `The number of most recent runs to skip.`,
+				Optional:    true,
+				Default:     0,
+			},
+			"ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `The IDs of the runs, most recent first.`,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"runs": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `The runs, most recent first.`,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"run_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"start_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"life_cycle_state": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"result_state": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state_message": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"run_page_url": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"has_more": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Whether there are more runs past limit.`,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceJobRunsRead(data *schema.ResourceData, client interface{}) error {
+	jobID, err := strconv.ParseInt(data.Get("job_id").(string), 10, 64)
+	if err != nil {
+		return err
+	}
+	runsReq := &db.RunsListRequest{
+		JobID:         jobID,
+		ActiveOnly:    data.Get("active_only").(bool),
+		CompletedOnly: data.Get("completed_only").(bool),
+		Offset:        int32(data.Get("offset").(int)),
+		Limit:         int32(data.Get("limit").(int)),
+	}
+
+	runsRes, err := client.(*providerMeta).client.Jobs().ListRuns(
+		context.Background(),
+		runsReq,
+	)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(runsRes.Runs))
+	runsData := make([]interface{}, len(runsRes.Runs))
+	for i, run := range runsRes.Runs {
+		ids[i] = strconv.FormatInt(run.RunID, 10)
+		runsData[i] = flattenRun(run)
+	}
+
+	id, err := hashstructure.Hash(runsReq, nil)
+	if err != nil {
+		return err
+	}
+	data.SetId(fmt.Sprintf("%d", id))
+
+	if err := data.Set("ids", ids); err != nil {
+		return err
+	}
+	data.Set("has_more", runsRes.HasMore)
+	return data.Set("runs", runsData)
+}
@@ -0,0 +1,156 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+func dataSourceNodeType() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNodeTypeRead,
+		Schema: map[string]*schema.Schema{
+			"min_cores": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Minimum number of CPU cores of the node type.`,
+				Optional:    true,
+				Default:     0,
+			},
+			"min_memory_gb": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Minimum amount of memory of the node type in GB.`,
+				Optional:    true,
+				Default:     0,
+			},
+			"local_disk": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Only pick node types with local disks.`,
+				Optional:    true,
+				Default:     false,
+			},
+			"category": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Category of the node type, e.g. General Purpose
+				or Memory Optimized. The comparison is case insensitive.`,
+				Optional: true,
+			},
+			"instance_family": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Instance family the node type belongs to,
+				e.g. m5d on AWS, Standard_DS on Azure or n2-highmem on GCP.`,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Description of the selected node type.`,
+				Computed:    true,
+			},
+			"cores": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Description: `Number of CPU cores of the selected node type.`,
+				Computed:    true,
+			},
+			"memory_gb": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Memory of the selected node type in GB.`,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceNodeTypeRead(data *schema.ResourceData, client interface{}) error {
+	nodeTypes, err := client.(*providerMeta).client.Cluster().ListNodeTypes(
+		context.Background(),
+	)
+	if err != nil {
+		return err
+	}
+
+	minCores := float32(data.Get("min_cores").(int))
+	minMemoryMB := int32(data.Get("min_memory_gb").(int) * 1024)
+	localDisk := data.Get("local_disk").(bool)
+	category := data.Get("category").(string)
+	family := data.Get("instance_family").(string)
+	cloud := client.(*providerMeta).config.cloud
+
+	matches := []db.NodeType{}
+	for _, nodeType := range nodeTypes {
+		if nodeType.IsDeprecated {
+			continue
+		}
+		if nodeType.NumCores < minCores || nodeType.MemoryMB < minMemoryMB {
+			continue
+		}
+		if localDisk && nodeTypeLocalDisks(nodeType) == 0 {
+			continue
+		}
+		if len(category) > 0 && !strings.EqualFold(nodeType.Category, category) {
+			continue
+		}
+		if len(family) > 0 && nodeTypeFamily(cloud, nodeType.NodeTypeID) != family {
+			continue
+		}
+		matches = append(matches, nodeType)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no node type matches the given criteria")
+	}
+
+	// smallest first, by cores and then by memory
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].NumCores != matches[j].NumCores {
+			return matches[i].NumCores < matches[j].NumCores
+		}
+		if matches[i].MemoryMB != matches[j].MemoryMB {
+			return matches[i].MemoryMB < matches[j].MemoryMB
+		}
+		return matches[i].NodeTypeID < matches[j].NodeTypeID
+	})
+
+	data.SetId(matches[0].NodeTypeID)
+	data.Set("description", matches[0].Description)
+	data.Set("cores", float64(matches[0].NumCores))
+	data.Set("memory_gb", int(matches[0].MemoryMB/1024))
+
+	return nil
+}
+
+func nodeTypeLocalDisks(nodeType db.NodeType) int32 {
+	if nodeType.NodeInstanceType == nil {
+		return 0
+	}
+	return nodeType.NodeInstanceType.LocalDisks
+}
+
+// nodeTypeFamily returns the instance family of a node type id in cloud:
+// m5d for m5d.large on AWS, Standard_DS for Standard_DS3_v2 on Azure and
+// n2-highmem for n2-highmem-8 on GCP.
+func nodeTypeFamily(cloud, nodeTypeID string) string {
+	switch cloud {
+	case cloudAzure:
+		const prefix = "Standard_"
+		if !strings.HasPrefix(nodeTypeID, prefix) {
+			return nodeTypeID
+		}
+		size := strings.IndexAny(nodeTypeID[len(prefix):], "0123456789")
+		if size < 0 {
+			return nodeTypeID
+		}
+		return nodeTypeID[:len(prefix)+size]
+	case cloudGCP:
+		if i := strings.LastIndex(nodeTypeID, "-"); i >= 0 {
+			return nodeTypeID[:i]
+		}
+		return nodeTypeID
+	}
+	if i := strings.Index(nodeTypeID, "."); i >= 0 {
+		return nodeTypeID[:i]
+	}
+	return nodeTypeID
+}
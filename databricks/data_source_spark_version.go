@@ -0,0 +1,191 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+var (
+	sparkVersionRuntimeRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)*)\.x`)
+	sparkVersionSparkRegexp   = regexp.MustCompile(`Apache Spark (\d+(?:\.\d+)*)`)
+	sparkVersionScalaRegexp   = regexp.MustCompile(`scala(\d+(?:\.\d+)*)$`)
+)
+
+func dataSourceSparkVersion() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSparkVersionRead,
+		Schema: map[string]*schema.Schema{
+			"latest": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Pick the most recent runtime if more than one
+				matches. If false, it is an error for more than one runtime to
+				match.`,
+				Optional: true,
+				Default:  true,
+			},
+			"long_term_support": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Only pick Long Term Support runtimes.`,
+				Optional:    true,
+				Default:     false,
+			},
+			"ml": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Pick a Machine Learning runtime.`,
+				Optional:    true,
+				Default:     false,
+			},
+			"gpu": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Pick a runtime with GPU support. Only Machine
+				Learning runtimes come with GPU support.`,
+				Optional: true,
+				Default:  false,
+			},
+			"scala": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Scala version of the runtime, e.g. 2.12.`,
+				Optional:    true,
+				Default:     "2.12",
+			},
+			"spark_version": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Minimum Apache Spark version of the runtime, e.g.
+				3.0.`,
+				Optional: true,
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Human readable name of the selected runtime.`,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// sparkVersion is a runtime parsed from its key and name.
+type sparkVersion struct {
+	key             string
+	name            string
+	runtimeVersion  string
+	sparkVersion    string
+	scalaVersion    string
+	longTermSupport bool
+	ml              bool
+	gpu             bool
+}
+
+func parseSparkVersion(version db.SparkVersion) (sparkVersion, bool) {
+	runtimeMatch := sparkVersionRuntimeRegexp.FindStringSubmatch(version.Key)
+	sparkMatch := sparkVersionSparkRegexp.FindStringSubmatch(version.Name)
+	scalaMatch := sparkVersionScalaRegexp.FindStringSubmatch(version.Key)
+	if runtimeMatch == nil || sparkMatch == nil || scalaMatch == nil {
+		return sparkVersion{}, false
+	}
+
+	// custom runtimes like photon, genomics or light are not selectable
+	for _, variant := range []string{"photon", "hls", "apache-spark", "aarch64"} {
+		if strings.Contains(version.Key, variant) {
+			return sparkVersion{}, false
+		}
+	}
+
+	return sparkVersion{
+		key:             version.Key,
+		name:            version.Name,
+		runtimeVersion:  runtimeMatch[1],
+		sparkVersion:    sparkMatch[1],
+		scalaVersion:    scalaMatch[1],
+		longTermSupport: strings.Contains(version.Name, "LTS"),
+		ml:              strings.Contains(version.Key, "-ml-"),
+		gpu:             strings.Contains(version.Key, "-gpu-"),
+	}, true
+}
+
+func dataSourceSparkVersionRead(data *schema.ResourceData, client interface{}) error {
+	versions, err := client.(*providerMeta).client.Cluster().SparkVersions(
+		context.Background(),
+	)
+	if err != nil {
+		return err
+	}
+
+	minSparkVersion := data.Get("spark_version").(string)
+	matches := []sparkVersion{}
+	for _, version := range versions {
+		parsed, ok := parseSparkVersion(version)
+		if !ok {
+			continue
+		}
+		if data.Get("long_term_support").(bool) && !parsed.longTermSupport {
+			continue
+		}
+		if parsed.ml != data.Get("ml").(bool) {
+			continue
+		}
+		if parsed.gpu != data.Get("gpu").(bool) {
+			continue
+		}
+		if parsed.scalaVersion != data.Get("scala").(string) {
+			continue
+		}
+		if len(minSparkVersion) > 0 &&
+			compareVersions(parsed.sparkVersion, minSparkVersion) < 0 {
+			continue
+		}
+		matches = append(matches, parsed)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no spark version matches the given criteria")
+	}
+	if len(matches) > 1 && !data.Get("latest").(bool) {
+		keys := make([]string, len(matches))
+		for i, match := range matches {
+			keys[i] = match.key
+		}
+		return fmt.Errorf(
+			"more than one spark version matches the given criteria: %s",
+			strings.Join(keys, ", "),
+		)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareVersions(
+			matches[i].runtimeVersion,
+			matches[j].runtimeVersion,
+		) > 0
+	})
+
+	data.SetId(matches[0].key)
+	data.Set("name", matches[0].name)
+
+	return nil
+}
+
+// compareVersions compares two dotted version strings, returning a negative
+// number if a < b, zero if they are equal and a positive number if a > b.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+	return 0
+}
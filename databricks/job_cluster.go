@@ -0,0 +1,180 @@
+package databricks
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+// newClusterSchema is the schema of a cluster that is created for a run and
+// terminated once the run finishes.
+func newClusterSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"spark_version": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The Spark version of the cluster.`,
+					Required:    true,
+				},
+				"node_type": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The node type of the Spark nodes. Required unless instance_pool_id is set.`,
+					Optional:    true,
+				},
+				"driver_node_type": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The node type of the Spark driver, node_type if unset.`,
+					Optional:    true,
+				},
+				"instance_pool_id": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The instance pool the nodes of the cluster are taken from.`,
+					Optional:    true,
+				},
+				"policy_id": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The cluster policy the cluster is validated against.`,
+					Optional:    true,
+				},
+				"num_workers": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `Number of worker nodes, the cluster autoscales between min_workers and max_workers if unset.`,
+					Optional:    true,
+				},
+				"min_workers": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `Minimum number of worker nodes when autoscaling.`,
+					Optional:    true,
+					Default:     0,
+				},
+				"max_workers": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `Maximum number of worker nodes when autoscaling.`,
+					Optional:    true,
+					Default:     1,
+				},
+				"spark_conf": &schema.Schema{
+					Type:        schema.TypeMap,
+					Description: `Spark configuration key-value pairs.`,
+					Optional:    true,
+				},
+				"spark_env": &schema.Schema{
+					Type:        schema.TypeMap,
+					Description: `Environment variables of the Spark nodes.`,
+					Optional:    true,
+				},
+				"tags": &schema.Schema{
+					Type:        schema.TypeMap,
+					Description: `Tags of the cluster, merged with the default_tags of the provider.`,
+					Optional:    true,
+				},
+				"aws_attributes":   clusterAWSAttributesSchema(),
+				"azure_attributes": clusterAzureAttributesSchema(),
+				"gcp_attributes":   clusterGCPAttributesSchema(),
+			},
+		},
+	}
+}
+
+// getNewCluster converts the new_cluster block in clusters into a cluster
+// request, or returns nil if it is not set.
+func getNewCluster(
+	clusters []interface{},
+	client interface{},
+) (*db.ClusterCreateRequest, error) {
+	if len(clusters) == 0 || clusters[0] == nil {
+		return nil, nil
+	}
+	clusterMap := clusters[0].(map[string]interface{})
+
+	nodeType := clusterMap["node_type"].(string)
+	poolID := clusterMap["instance_pool_id"].(string)
+	if len(nodeType) == 0 && len(poolID) == 0 {
+		return nil, fmt.Errorf(
+			"one of node_type or instance_pool_id must be set on new_cluster",
+		)
+	}
+
+	sparkConf, err := stringMap(clusterMap["spark_conf"].(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	sparkEnv, err := stringMap(clusterMap["spark_env"].(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	tags, err := mergeDefaultTags(
+		client,
+		clusterMap["tags"].(map[string]interface{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &db.ClusterCreateRequest{
+		SparkVersion:     clusterMap["spark_version"].(string),
+		NodeTypeID:       nodeType,
+		DriverNodeTypeID: clusterMap["driver_node_type"].(string),
+		InstancePoolID:   poolID,
+		PolicyID:         clusterMap["policy_id"].(string),
+		SparkConf:        sparkConf,
+		SparkEnvVars:     sparkEnv,
+		CustomTags:       tags,
+		AWSAttributes: getClusterAWSAttributes(
+			clusterMap["aws_attributes"].([]interface{}),
+		),
+		AzureAttributes: getClusterAzureAttributes(
+			clusterMap["azure_attributes"].([]interface{}),
+		),
+		GCPAttributes: getClusterGCPAttributes(
+			clusterMap["gcp_attributes"].([]interface{}),
+		),
+	}
+	if numWorkers := int32(clusterMap["num_workers"].(int)); numWorkers > 0 {
+		cluster.NumWorkers = &numWorkers
+	} else {
+		cluster.Autoscale = &db.Autoscale{
+			Min: int32(clusterMap["min_workers"].(int)),
+			Max: int32(clusterMap["max_workers"].(int)),
+		}
+	}
+
+	return cluster, nil
+}
+
+// validateNewCluster validates the new_cluster block at prefix against the
+// workspace the same way clusters are validated.
+func validateNewCluster(
+	diff *schema.ResourceDiff,
+	client interface{},
+	prefix string,
+) error {
+	clusters := diff.Get(prefix + "new_cluster").([]interface{})
+	if len(clusters) == 0 || clusters[0] == nil {
+		return nil
+	}
+	prefix += "new_cluster.0."
+
+	if err := validateClusterCloud(diff, client, prefix); err != nil {
+		return err
+	}
+	return validateClusterWorkspace(diff, client, prefix)
+}
+
+// stringMap converts a map attribute into a map of strings.
+func stringMap(m map[string]interface{}) (map[string]string, error) {
+	strs := map[string]string{}
+	for key, val := range m {
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Value %#v of %s is not a string", val, key)
+		}
+		strs[key] = valStr
+	}
+	return strs, nil
+}
@@ -0,0 +1,109 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	db "github.com/medivo/databricks-go"
+)
+
+// runIsTerminal reports whether a run has finished, successfully or not.
+func runIsTerminal(run *db.Run) bool {
+	if run.State == nil {
+		return false
+	}
+	switch run.State.LifeCycleState {
+	case db.RunLifeCycleStateTerminated,
+		db.RunLifeCycleStateSkipped,
+		db.RunLifeCycleStateInternalError:
+		return true
+	}
+	return false
+}
+
+// runError returns an error with the state message of a finished run that
+// did not succeed.
+func runError(run *db.Run) error {
+	if run.State == nil {
+		return fmt.Errorf("run %d has no state", run.RunID)
+	}
+	if run.State.ResultState != nil &&
+		*run.State.ResultState == db.RunResultStateSuccess {
+		return nil
+	}
+
+	result := string(run.State.LifeCycleState)
+	if run.State.ResultState != nil {
+		result = string(*run.State.ResultState)
+	}
+	return fmt.Errorf(
+		"run %d finished with %s: %s (%s)",
+		run.RunID,
+		result,
+		run.State.StateMessage,
+		run.RunPageURL,
+	)
+}
+
+// waitForRun waits for a run to finish and returns it.
+func waitForRun(
+	ctx context.Context,
+	client interface{},
+	runID int64,
+	timeout time.Duration,
+) (*db.Run, error) {
+	var run *db.Run
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		var err error
+		run, err = client.(*providerMeta).client.Jobs().GetRun(ctx, runID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if run.State == nil {
+			return resource.RetryableError(fmt.Errorf(
+				"run %d has no state yet", runID,
+			))
+		}
+		if !runIsTerminal(run) {
+			return resource.RetryableError(fmt.Errorf(
+				"run %d is %s", runID, run.State.LifeCycleState,
+			))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// flattenRun converts a run into the attributes of the run data sources.
+func flattenRun(run db.Run) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"run_id":       strconv.FormatInt(run.RunID, 10),
+		"start_time":   runTime(run.StartTime),
+		"end_time":     runTime(run.EndTime),
+		"run_page_url": run.RunPageURL,
+	}
+	if run.State != nil {
+		attrs["life_cycle_state"] = string(run.State.LifeCycleState)
+		attrs["state_message"] = run.State.StateMessage
+		if run.State.ResultState != nil {
+			attrs["result_state"] = string(*run.State.ResultState)
+		}
+	}
+	return attrs
+}
+
+// runTime formats a time of a run in milliseconds since the epoch, which is
+// 0 until the time is known.
+func runTime(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)).Format(time.RFC3339)
+}
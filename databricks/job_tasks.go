@@ -0,0 +1,572 @@
+package databricks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+// forceNew marks a schema and every attribute nested in it as forcing a new
+// resource when it changes, optionally conflicting with other attributes.
+func forceNew(s *schema.Schema, conflictsWith ...string) *schema.Schema {
+	setForceNew(s)
+	return withConflicts(s, conflictsWith...)
+}
+
+// setForceNew sets ForceNew on s and on the attributes of its nested
+// blocks, as ForceNew on a block only covers the number of its elements.
+func setForceNew(s *schema.Schema) {
+	if !s.Computed || s.Optional || s.Required {
+		s.ForceNew = true
+	}
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		for _, nested := range elem.Schema {
+			setForceNew(nested)
+		}
+	}
+}
+
+// withConflicts makes a schema conflict with other attributes.
+func withConflicts(s *schema.Schema, conflictsWith ...string) *schema.Schema {
+	s.ConflictsWith = conflictsWith
+	return s
+}
+
+// The task schemas are shared by the resources that run Spark tasks.
+
+func notebookTaskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"notebook_path": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: `The absolute path of the notebook to be run in the Databricks Workspace. This path must begin with a slash. When the job has a git_source, the path is relative to the repository instead.`,
+				},
+				"base_parameters": {
+					Type:     schema.TypeMap,
+					Optional: true,
+					Description: `Base parameters to be used for each run of this job. If the run is initiated by a call to run-now with parameters specified, the two parameters maps will be merged. If the same key is specified in base_parameters and in run-now, the value from run-now will be used.
+
+If the notebook takes a parameter that is not specified in the job’s base_parameters or the run-now override parameters, the default value from the notebook will be used.
+
+These parameters can be retrieved in a notebook by using dbutils.widgets.get().
+`,
+				},
+			},
+		},
+	}
+}
+
+func sparkPythonTaskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"python_file": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: `The absolute path of the notebook to be run in the Databricks Workspace. This path must begin with a slash.`,
+				},
+				"parameters": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: `Command line parameters that will be passed to the Python file.`,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func sparkSubmitTaskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"parameters": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: `Command line parameters that will be passed to spark submit.`,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func sparkJarTaskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"main_class_name": {
+					Type:     schema.TypeString,
+					Required: true,
+					Description: `The full name of the class containing the main method to be executed. This class must be contained in a JAR provided as a library.
+
+The code should use SparkContext.getOrCreate to obtain a Spark context; otherwise, runs of the job will fail.`,
+				},
+				"parameters": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: `Parameters that will be passed to the main method.`,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func getNotebookTask(noteSchema *schema.Set) *db.NotebookTask {
+	if noteSchema.Len() == 0 {
+		return nil
+	}
+	notebookTask := &db.NotebookTask{}
+
+	for _, noteData := range noteSchema.List() {
+		noteMap := noteData.(map[string]interface{})
+		if pathIface, ok := noteMap["notebook_path"]; ok {
+			notebookTask.NotebookPath = pathIface.(string)
+		}
+		if paramMap, ok := noteMap["base_parameters"]; ok {
+			params := []db.ParamPair{}
+			for key, val := range paramMap.(map[string]interface{}) {
+				params = append(params,
+					db.ParamPair{
+						Key:   key,
+						Value: val.(string),
+					},
+				)
+			}
+			notebookTask.BaseParameters = params
+		}
+	}
+
+	return notebookTask
+}
+
+func getSparkJarTask(taskSchema *schema.Set) *db.SparkJarTask {
+	if taskSchema.Len() == 0 {
+		return nil
+	}
+	jarTask := &db.SparkJarTask{}
+
+	for _, taskData := range taskSchema.List() {
+		taskMap := taskData.(map[string]interface{})
+		if mainIface, ok := taskMap["main_class_name"]; ok {
+			jarTask.MainClassName = mainIface.(string)
+		}
+		if paramSlice, ok := taskMap["parameters"]; ok {
+			params := make([]string, len(paramSlice.([]interface{})))
+			for i, val := range paramSlice.([]interface{}) {
+				params[i] = val.(string)
+			}
+			jarTask.Parameters = params
+		}
+	}
+
+	return jarTask
+}
+
+func getSparkPythonTask(taskSchema *schema.Set) *db.SparkPythonTask {
+	if taskSchema.Len() == 0 {
+		return nil
+	}
+	pythonTask := &db.SparkPythonTask{}
+
+	for _, taskData := range taskSchema.List() {
+		taskMap := taskData.(map[string]interface{})
+		if mainIface, ok := taskMap["python_file"]; ok {
+			pythonTask.PythonFile = mainIface.(string)
+		}
+		if paramSlice, ok := taskMap["parameters"]; ok {
+			params := make([]string, len(paramSlice.([]interface{})))
+			for i, val := range paramSlice.([]interface{}) {
+				params[i] = val.(string)
+			}
+			pythonTask.Parameters = params
+		}
+	}
+
+	return pythonTask
+}
+
+func getSparkSubmitTask(taskSchema *schema.Set) *db.SparkSubmitTask {
+	if taskSchema.Len() == 0 {
+		return nil
+	}
+	submitTask := &db.SparkSubmitTask{}
+
+	for _, taskData := range taskSchema.List() {
+		taskMap := taskData.(map[string]interface{})
+		if paramSlice, ok := taskMap["parameters"]; ok {
+			params := make([]string, len(paramSlice.([]interface{})))
+			for i, val := range paramSlice.([]interface{}) {
+				params[i] = val.(string)
+			}
+			submitTask.Parameters = params
+		}
+	}
+
+	return submitTask
+}
+
+// jobTaskSchema is the schema of the tasks of a multi-task job.
+func jobTaskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: `The tasks of the job, run in the order given by depends_on.`,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"task_key": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `A name for the task, unique within the job.`,
+					Required:    true,
+				},
+				"description": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `An optional description of the task.`,
+					Optional:    true,
+				},
+				"depends_on": &schema.Schema{
+					Type:        schema.TypeList,
+					Description: `The task_key of the tasks that must succeed before this task runs.`,
+					Optional:    true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"existing_cluster_id": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The id of an existing cluster to run the task on.`,
+					Optional:    true,
+				},
+				"job_cluster_key": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `The job_cluster_key of a job_cluster to run the task on.`,
+					Optional:    true,
+				},
+				"new_cluster":       newClusterSchema(),
+				"notebook_task":     notebookTaskSchema(),
+				"spark_python_task": sparkPythonTaskSchema(),
+				"spark_submit_task": sparkSubmitTaskSchema(),
+				"spark_jar_task":    sparkJarTaskSchema(),
+				"libraries": &schema.Schema{
+					Type:     schema.TypeSet,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: librarySchema(),
+					},
+				},
+				"timeout_seconds": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `Task timeout in seconds.`,
+					Optional:    true,
+				},
+				"max_retries": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `An optional maximum number of times to retry an unsuccessful run of the task.`,
+					Optional:    true,
+				},
+				"min_retry_interval_millis": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: `An optional minimal interval in milliseconds between attempts.`,
+					Optional:    true,
+				},
+				"retry_on_timeout": &schema.Schema{
+					Type:        schema.TypeBool,
+					Description: `Whether to retry the task when it times out.`,
+					Optional:    true,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// jobClusterSchema is the schema of the clusters shared by the tasks of a
+// job.
+func jobClusterSchema() *schema.Schema {
+	newCluster := newClusterSchema()
+	newCluster.Optional = false
+	newCluster.Required = true
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: `Clusters that can be shared by the tasks of the job.`,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"job_cluster_key": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: `A name for the cluster, unique within the job.`,
+					Required:    true,
+				},
+				"new_cluster": newCluster,
+			},
+		},
+	}
+}
+
+func getJobTasks(tasksData []interface{}, client interface{}) ([]db.JobTask, error) {
+	tasks := []db.JobTask{}
+	for _, taskData := range tasksData {
+		taskMap := taskData.(map[string]interface{})
+		taskKey := taskMap["task_key"].(string)
+
+		newCluster, err := getNewCluster(
+			taskMap["new_cluster"].([]interface{}),
+			client,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %s", taskKey, err)
+		}
+		task := db.JobTask{
+			TaskKey:         taskKey,
+			Description:     taskMap["description"].(string),
+			JobClusterKey:   taskMap["job_cluster_key"].(string),
+			NewCluster:      newCluster,
+			NotebookTask:    getNotebookTask(taskMap["notebook_task"].(*schema.Set)),
+			SparkJarTask:    getSparkJarTask(taskMap["spark_jar_task"].(*schema.Set)),
+			SparkPythonTask: getSparkPythonTask(taskMap["spark_python_task"].(*schema.Set)),
+			SparkSubmitTask: getSparkSubmitTask(taskMap["spark_submit_task"].(*schema.Set)),
+			Libraries:       getLibraries(taskMap["libraries"].(*schema.Set)),
+		}
+		if clusterID := taskMap["existing_cluster_id"].(string); len(clusterID) > 0 {
+			task.ExistingClusterID = &clusterID
+		}
+		for _, dep := range taskMap["depends_on"].([]interface{}) {
+			task.DependsOn = append(task.DependsOn, db.TaskDependency{
+				TaskKey: dep.(string),
+			})
+		}
+
+		if err := validateJobTask(task); err != nil {
+			return nil, err
+		}
+
+		if to := int32(taskMap["timeout_seconds"].(int)); to > 0 {
+			task.TimeoutSeconds = &to
+		}
+		if retries := int32(taskMap["max_retries"].(int)); retries > 0 {
+			task.MaxRetries = &retries
+		}
+		if retryInter := int32(taskMap["min_retry_interval_millis"].(int)); retryInter > 0 {
+			task.MinRetryIntervalMillis = &retryInter
+		}
+		retryOnTO := taskMap["retry_on_timeout"].(bool)
+		task.RetryOnTimeout = &retryOnTO
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func getJobClusters(clustersData []interface{}, client interface{}) ([]db.JobCluster, error) {
+	clusters := []db.JobCluster{}
+	for _, clusterData := range clustersData {
+		clusterMap := clusterData.(map[string]interface{})
+		clusterKey := clusterMap["job_cluster_key"].(string)
+
+		newCluster, err := getNewCluster(
+			clusterMap["new_cluster"].([]interface{}),
+			client,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("job_cluster %s: %s", clusterKey, err)
+		}
+		clusters = append(clusters, db.JobCluster{
+			JobClusterKey: clusterKey,
+			NewCluster:    newCluster,
+		})
+	}
+
+	return clusters, nil
+}
+
+// jobTaskOutline converts a task block into a task with just enough set to
+// be checked by validateJobTask during plan, before the values of the task
+// are used to build the request.
+func jobTaskOutline(taskMap map[string]interface{}) db.JobTask {
+	task := db.JobTask{
+		TaskKey:       taskMap["task_key"].(string),
+		JobClusterKey: taskMap["job_cluster_key"].(string),
+	}
+	if clusterID := taskMap["existing_cluster_id"].(string); len(clusterID) > 0 {
+		task.ExistingClusterID = &clusterID
+	}
+	if len(taskMap["new_cluster"].([]interface{})) > 0 {
+		task.NewCluster = &db.ClusterCreateRequest{}
+	}
+	if taskMap["notebook_task"].(*schema.Set).Len() > 0 {
+		task.NotebookTask = &db.NotebookTask{}
+	}
+	if taskMap["spark_jar_task"].(*schema.Set).Len() > 0 {
+		task.SparkJarTask = &db.SparkJarTask{}
+	}
+	if taskMap["spark_python_task"].(*schema.Set).Len() > 0 {
+		task.SparkPythonTask = &db.SparkPythonTask{}
+	}
+	if taskMap["spark_submit_task"].(*schema.Set).Len() > 0 {
+		task.SparkSubmitTask = &db.SparkSubmitTask{}
+	}
+	return task
+}
+
+// validateJobTask checks that a task runs exactly one kind of task on
+// exactly one cluster.
+func validateJobTask(task db.JobTask) error {
+	clusters := 0
+	if task.ExistingClusterID != nil {
+		clusters++
+	}
+	if len(task.JobClusterKey) > 0 {
+		clusters++
+	}
+	if task.NewCluster != nil {
+		clusters++
+	}
+	if clusters != 1 {
+		return fmt.Errorf(
+			"task %s must set exactly one of existing_cluster_id, job_cluster_key or new_cluster",
+			task.TaskKey,
+		)
+	}
+
+	if err := validateTaskKind(task); err != nil {
+		return fmt.Errorf("task %s %s", task.TaskKey, err)
+	}
+
+	return nil
+}
+
+// validateTaskKind checks that exactly one kind of task is set.
+func validateTaskKind(task db.JobTask) error {
+	kinds := 0
+	if task.NotebookTask != nil {
+		kinds++
+	}
+	if task.SparkJarTask != nil {
+		kinds++
+	}
+	if task.SparkPythonTask != nil {
+		kinds++
+	}
+	if task.SparkSubmitTask != nil {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf(
+			"must set exactly one of notebook_task, spark_jar_task, spark_python_task or spark_submit_task",
+		)
+	}
+	return nil
+}
+
+// validateJobTaskGraph checks that task and job cluster keys are unique,
+// that tasks only refer to keys that exist and that the dependencies of the
+// tasks don't form a cycle.
+func validateJobTaskGraph(tasksData, clustersData []interface{}) error {
+	clusterKeys := map[string]bool{}
+	for _, clusterData := range clustersData {
+		key := clusterData.(map[string]interface{})["job_cluster_key"].(string)
+		if clusterKeys[key] {
+			return fmt.Errorf("job_cluster_key %q is not unique", key)
+		}
+		clusterKeys[key] = true
+	}
+
+	taskKeys := []string{}
+	dependsOn := map[string][]string{}
+	for _, taskData := range tasksData {
+		taskMap := taskData.(map[string]interface{})
+		key := taskMap["task_key"].(string)
+		if _, ok := dependsOn[key]; ok {
+			return fmt.Errorf("task_key %q is not unique", key)
+		}
+		taskKeys = append(taskKeys, key)
+		dependsOn[key] = []string{}
+		for _, dep := range taskMap["depends_on"].([]interface{}) {
+			dependsOn[key] = append(dependsOn[key], dep.(string))
+		}
+
+		clusterKey := taskMap["job_cluster_key"].(string)
+		if len(clusterKey) > 0 && !clusterKeys[clusterKey] {
+			return fmt.Errorf(
+				"task %s uses unknown job_cluster_key %q",
+				key,
+				clusterKey,
+			)
+		}
+	}
+
+	for _, key := range taskKeys {
+		for _, dep := range dependsOn[key] {
+			if _, ok := dependsOn[dep]; !ok {
+				return fmt.Errorf("task %s depends on unknown task %q", key, dep)
+			}
+		}
+	}
+
+	// depth first search, a task that is reached again while its own
+	// dependencies are being visited is part of a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := map[string]int{}
+	var path []string
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch states[key] {
+		case visiting:
+			start := 0
+			for i, pathKey := range path {
+				if pathKey == key {
+					start = i
+				}
+			}
+			return fmt.Errorf(
+				"task dependencies form a cycle: %s -> %s",
+				strings.Join(path[start:], " -> "),
+				key,
+			)
+		case visited:
+			return nil
+		}
+		states[key] = visiting
+		path = append(path, key)
+		for _, dep := range dependsOn[key] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[key] = visited
+		return nil
+	}
+	for _, key := range taskKeys {
+		if err := visit(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package databricks
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+// librarySchema is the schema of a single library, shared by job libraries
+// and cluster libraries.
+func librarySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"jar": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: `If jar, URI of the jar to be installed. DBFS and S3 URIs are supported.`,
+		},
+		"egg": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: `If egg, URI of the egg to be installed. DBFS and S3 URIs are supported.`,
+		},
+		"whl": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: `If whl, URI of the wheel or zipped wheels to be installed. DBFS and S3 URIs are supported. For example: { "whl": "dbfs:/my/whl" } or { "whl": "s3://my-bucket/whl" }. If S3 is used, make sure the cluster has read access on the library. You may need to launch the cluster with an IAM role to access the S3 URI. Also the wheel file name needs to use the correct convention. If zipped wheels are to be installed, the file name suffix should be .wheelhouse.zip.`,
+		},
+		"pypi": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: `If pypi, specification of a PyPi library to be installed.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"package": &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: `The name of the PyPi package to install. An optional exact version specification is also supported. Examples: simplejson and simplejson==3.8.0.`,
+					},
+					"repo": &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: `The repository where the package can be found. If not specified, the default pip index is used.`,
+					},
+				},
+			},
+		},
+		"maven": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: `If maven, specification of a Maven library to be installed.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"coordinates": &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: `Gradle-style Maven coordinates. For example: org.jsoup:jsoup:1.7.2.`,
+					},
+					"repo": &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: `Maven repo to install the Maven package from. If omitted, both Maven Central Repository and Spark Packages are searched.`,
+					},
+					"exclusions": &schema.Schema{
+						Type:     schema.TypeList,
+						Optional: true,
+						Description: `List of dependences to exclude. For example: ["slf4j:slf4j", "*:hadoop-client"].
+
+`,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"cran": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: `If cran, specification of a CRAN library to be installed.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"package": &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: `The name of the CRAN package to install.`,
+					},
+					"repo": &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: `The repository where the package can be found. If not specified, the default CRAN repo is used.`,
+					},
+				},
+			},
+		},
+	}
+}
+
+// libraryFromMap converts a single library block into a db.Library.
+func libraryFromMap(lib map[string]interface{}) db.Library {
+	library := db.Library{}
+	for libType, libTypeData := range lib {
+		switch libType {
+		case "jar":
+			jar := libTypeData.(string)
+			if len(jar) > 0 {
+				library.Jar = &jar
+			}
+		case "egg":
+			egg := libTypeData.(string)
+			if len(egg) > 0 {
+				library.Egg = &egg
+			}
+		case "whl":
+			whl := libTypeData.(string)
+			if len(whl) > 0 {
+				library.Whl = &whl
+			}
+		case "pypi":
+			pypiTfData := libTypeData.(*schema.Set)
+			if pypiTfData.Len() == 0 {
+				continue
+			}
+			pypiLibrary := db.PythonPyPiLibrary{}
+			for _, setData := range pypiTfData.List() {
+				setDataMap := setData.(map[string]interface{})
+				packageStr := setDataMap["package"].(string)
+				if len(packageStr) > 0 {
+					pypiLibrary.Package = packageStr
+				}
+				repo, ok := setDataMap["repo"]
+				if ok {
+					repoStr := repo.(string)
+					if len(repoStr) > 0 {
+						pypiLibrary.Repo = &repoStr
+					}
+				}
+			}
+			library.Pypi = &pypiLibrary
+		case "maven":
+			mavenTfData := libTypeData.(*schema.Set)
+			if mavenTfData.Len() == 0 {
+				continue
+			}
+			mavenLibrary := db.MavenLibrary{}
+			for _, setData := range mavenTfData.List() {
+				setDataMap := setData.(map[string]interface{})
+				coords := setDataMap["coordinates"].(string)
+				if len(coords) > 0 {
+					mavenLibrary.Coordinates = coords
+				}
+				repo, ok := setDataMap["repo"]
+				if ok {
+					repoStr := repo.(string)
+					if len(repoStr) > 0 {
+						mavenLibrary.Repo = &repoStr
+					}
+				}
+				// this is tedious....
+				exIfaces := setDataMap["exclusions"].([]interface{})
+				exclusions := make([]string, len(exIfaces))
+				for i, exIface := range exIfaces {
+					exclusions[i] = exIface.(string)
+				}
+				mavenLibrary.Exclusions = exclusions
+			}
+			library.Maven = &mavenLibrary
+		case "cran":
+			cranTfData := libTypeData.(*schema.Set)
+			if cranTfData.Len() == 0 {
+				continue
+			}
+			cranLibrary := db.RCranLibrary{}
+			for _, setData := range cranTfData.List() {
+				setDataMap := setData.(map[string]interface{})
+				packageStr := setDataMap["package"].(string)
+				if len(packageStr) > 0 {
+					cranLibrary.Package = packageStr
+				}
+				repo, ok := setDataMap["repo"]
+				if ok {
+					repoStr := repo.(string)
+					if len(repoStr) > 0 {
+						cranLibrary.Repo = &repoStr
+					}
+				}
+			}
+			library.Cran = &cranLibrary
+		}
+	}
+
+	return library
+}
+
+func getLibraries(libsData *schema.Set) []db.Library {
+	libs := []db.Library{}
+	for _, libData := range libsData.List() {
+		libs = append(libs, libraryFromMap(libData.(map[string]interface{})))
+	}
+
+	return libs
+}
@@ -1,7 +1,13 @@
 package databricks
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	db "github.com/medivo/databricks-go"
 )
@@ -10,23 +16,197 @@ import (
 func Provider() terraform.ResourceProvider {
 	return &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
-			"databricks_cluster": resourceCluster(),
-			"databricks_dbfs":    resourceDBFS(),
-			"databricks_groups":  resourceGroups(),
-			"databricks_job":     resourceJobs(),
+			"databricks_cluster":                  resourceCluster(),
+			"databricks_cluster_library":          resourceClusterLibrary(),
+			"databricks_cluster_policy":           resourceClusterPolicy(),
+			"databricks_dbfs":                     resourceDBFS(),
+			"databricks_groups":                   resourceGroups(),
+			"databricks_instance_pool":            resourceInstancePool(),
+			"databricks_job":                      resourceJobs(),
+			"databricks_job_run":                  resourceJobRun(),
+			"databricks_notification_destination": resourceNotificationDestination(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"databricks_cluster":        dataSourceCluster(),
+			"databricks_cluster_events": dataSourceClusterEvents(),
+			"databricks_clusters":       dataSourceClusters(),
+			"databricks_job_runs":       dataSourceJobRuns(),
+			"databricks_node_type":      dataSourceNodeType(),
+			"databricks_spark_version":  dataSourceSparkVersion(),
 		},
 		Schema: map[string]*schema.Schema{
 			"account": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type: schema.TypeString,
+				Description: `Account id of an AWS workspace, the workspace
+				is reached at <account>.cloud.databricks.com.`,
+				Optional:      true,
+				ConflictsWith: []string{"host"},
+			},
+			"host": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `URL of the workspace, e.g.
+				https://adb-123456789.0.azuredatabricks.net. Required unless
+				account is set.`,
+				Optional:      true,
+				ConflictsWith: []string{"account"},
+			},
+			"cloud": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Cloud the workspace runs in, one of aws, azure or
+				gcp. Guessed from host if unset.`,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					cloudAWS,
+					cloudAzure,
+					cloudGCP,
+				}, false),
+			},
+			"default_tags": &schema.Schema{
+				Type: schema.TypeMap,
+				Description: `Tags added to every cluster managed by the
+				provider, including the new_cluster of job tasks, job
+				clusters and job runs. Tags set on a resource take
+				precedence.`,
+				Optional: true,
 			},
 		},
-		ConfigureFunc: func(data *schema.ResourceData) (interface{}, error) {
-			println(data.Get("account").(string))
-			return db.NewClient(
-				data.Get("account").(string),
-				db.ClientHTTPClient(db.NetrcHTTPClient),
-			)
-		},
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+// Clouds a workspace can run in.
+const (
+	cloudAWS   = "aws"
+	cloudAzure = "azure"
+	cloudGCP   = "gcp"
+)
+
+// providerConfig holds the provider settings the resources need besides
+// the client itself.
+type providerConfig struct {
+	defaultTags map[string]string
+	cloud       string
+}
+
+// providerMeta is the meta the provider hands to its resources and data
+// sources.
+type providerMeta struct {
+	client *db.Client
+	config *providerConfig
+	cache  *workspaceCache
+}
+
+func providerConfigure(data *schema.ResourceData) (interface{}, error) {
+	account := data.Get("account").(string)
+	host := data.Get("host").(string)
+	if len(account) == 0 && len(host) == 0 {
+		return nil, fmt.Errorf("one of account or host must be set")
+	}
+
+	opts := []db.ClientOption{db.ClientHTTPClient(db.NetrcHTTPClient)}
+	if len(host) > 0 {
+		opts = append(opts, db.ClientHost(host))
+	}
+	client, err := db.NewClient(account, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &providerConfig{
+		defaultTags: map[string]string{},
+		cloud:       data.Get("cloud").(string),
+	}
+	if len(config.cloud) == 0 {
+		config.cloud = cloudFromHost(host)
+	}
+	for key, val := range data.Get("default_tags").(map[string]interface{}) {
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Tag value %#v is not a string", val)
+		}
+		config.defaultTags[key] = valStr
+	}
+
+	return &providerMeta{
+		client: client,
+		config: config,
+		cache:  &workspaceCache{},
+	}, nil
+}
+
+// cloudFromHost figures out which cloud a workspace runs in from its URL,
+// including the Azure Government and Azure China clouds. Account based
+// workspaces are always on AWS, as are hosts it doesn't recognize.
+func cloudFromHost(host string) string {
+	if u, err := url.Parse(host); err == nil && len(u.Host) > 0 {
+		host = u.Host
+	}
+	switch {
+	case strings.HasSuffix(host, ".azuredatabricks.net"),
+		strings.HasSuffix(host, ".databricks.azure.us"),
+		strings.HasSuffix(host, ".databricks.azure.cn"):
+		return cloudAzure
+	case strings.HasSuffix(host, ".gcp.databricks.com"):
+		return cloudGCP
+	}
+	return cloudAWS
+}
+
+// mergeDefaultTags adds the default_tags of the provider to tags, tags that
+// are set on the resource win.
+func mergeDefaultTags(
+	client interface{},
+	tags map[string]interface{},
+) ([]db.ClusterTag, error) {
+	merged := map[string]string{}
+	for key, val := range client.(*providerMeta).config.defaultTags {
+		merged[key] = val
+	}
+	for key, val := range tags {
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Tag value %#v is not a string", val)
+		}
+		merged[key] = valStr
+	}
+
+	clusterTags := []db.ClusterTag{}
+	for key, val := range merged {
+		clusterTags = append(clusterTags, db.ClusterTag{
+			Key:   key,
+			Value: val,
+		})
+	}
+	return clusterTags, nil
+}
+
+// withoutDefaultTags removes the tags added by the provider from clusterTags
+// unless they were set on the resource as well, so that they don't show up
+// as a diff.
+func withoutDefaultTags(
+	client interface{},
+	clusterTags []db.ClusterTag,
+	configured map[string]interface{},
+) map[string]string {
+	defaults := client.(*providerMeta).config.defaultTags
+	tags := map[string]string{}
+	for _, tag := range clusterTags {
+		_, isConfigured := configured[tag.Key]
+		if val, ok := defaults[tag.Key]; ok && val == tag.Value && !isConfigured {
+			continue
+		}
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+// isNotFound reports whether err is the API telling that the requested
+// object doesn't exist.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*db.APIError)
+	if !ok {
+		return false
 	}
+	return apiErr.StatusCode == http.StatusNotFound ||
+		apiErr.ErrorCode == "RESOURCE_DOES_NOT_EXIST"
 }
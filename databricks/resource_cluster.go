@@ -3,26 +3,43 @@ package databricks
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	db "github.com/medivo/databricks-go"
 )
 
+// Values of the restart_policy of a cluster.
+const (
+	clusterRestartIfRunning = "restart_if_running"
+	clusterApplyOnNextStart = "apply_on_next_start"
+	clusterFailIfRunning    = "fail_if_running"
+)
+
 func resourceCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceServerCreate,
-		Read:   resourceServerRead,
-		Update: resourceServerUpdate,
-		Delete: resourceServerDelete,
+		Create:        resourceServerCreate,
+		Read:          resourceServerRead,
+		Update:        resourceServerUpdate,
+		Delete:        resourceServerDelete,
+		CustomizeDiff: resourceClusterCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"cluster_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"spark_version": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "4.0.x-scala2.11",
+				Type: schema.TypeString,
+				Description: `The runtime version of the cluster, e.g. from the
+				databricks_spark_version data source.`,
+				Required: true,
 			},
 			"ssh_keys": &schema.Schema{
 				Type:     schema.TypeList,
@@ -38,10 +55,11 @@ func resourceCluster() *schema.Resource {
 			},
 			"node_type": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				Description: `This field encodes, through a single value, the
 				resources available to each of the Spark nodes in this
-				cluster.`,
+				cluster. Required unless instance_pool_id is set.`,
+				ConflictsWith: []string{"instance_pool_id"},
 			},
 			"driver_node_type": &schema.Schema{
 				Type:     schema.TypeString,
@@ -49,6 +67,21 @@ func resourceCluster() *schema.Resource {
 				Description: `The node type of the Spark driver. Note that this
 				field is optional; if unset, the driver node type will be set
 				as the same value as node_type_id defined above.`,
+				ConflictsWith: []string{"driver_instance_pool_id"},
+			},
+			"instance_pool_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: `The optional ID of the instance pool to which the
+				cluster belongs. When set the node type of the pool is used
+				instead of node_type.`,
+			},
+			"driver_instance_pool_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: `The optional ID of the instance pool to use for
+				the driver node. If unset, the driver uses the instance pool
+				set in instance_pool_id.`,
 			},
 			"num_workers": &schema.Schema{
 				Type: schema.TypeInt,
@@ -78,6 +111,27 @@ func resourceCluster() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"spark_conf": {
+				Type: schema.TypeMap,
+				Description: `Spark configuration key-value pairs. Settings
+				required by the cluster_mode are added automatically.`,
+				Optional: true,
+			},
+			"cluster_mode": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `One of standard, single_node or
+				high_concurrency. Fills in the spark_conf, tags and number of
+				workers the mode requires. high_concurrency also defaults
+				spark.databricks.repl.allowedLanguages to sql,python,r, which
+				spark_conf can override.`,
+				Optional: true,
+				Default:  clusterModeStandard,
+				ValidateFunc: validation.StringInSlice([]string{
+					clusterModeStandard,
+					clusterModeSingleNode,
+					clusterModeHighConcurrency,
+				}, false),
+			},
 			"autotermination_minutes": &schema.Schema{
 				Type: schema.TypeInt,
 				Description: `Automatically terminates the cluster after it is
@@ -104,114 +158,271 @@ func resourceCluster() *schema.Resource {
 				Default:  false,
 				Optional: true,
 			},
-			"aws_attributes": {
-				Type:     schema.TypeList,
+			"policy_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: `The ID of the cluster policy used to create the
+				cluster.`,
+			},
+			"restart_policy": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `What to do when a change requires a running
+				cluster to be restarted. restart_if_running restarts the
+				cluster and fail_if_running fails the apply.
+				apply_on_next_start leaves the running cluster as it is and
+				sets edit_pending, the change is then applied by the first
+				apply once the cluster is terminated.`,
+				Optional: true,
+				Default:  clusterRestartIfRunning,
+				ValidateFunc: validation.StringInSlice([]string{
+					clusterRestartIfRunning,
+					clusterApplyOnNextStart,
+					clusterFailIfRunning,
+				}, false),
+			},
+			"edit_pending": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Whether a change deferred by the
+				apply_on_next_start restart_policy is still to be applied to
+				the cluster.`,
+				Computed: true,
+			},
+			"is_pinned": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Pinned clusters keep their configuration after
+				they have been terminated for more than 30 days.`,
+				Default:  false,
 				Optional: true,
+			},
+			"docker_image": {
+				Type:        schema.TypeList,
+				Description: `Custom docker image for the cluster nodes.`,
+				Optional:    true,
+				MaxItems:    1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"first_on_demand": {
-							Description: `The first first_on_demand nodes of
-							the cluster will be placed on on-demand instances.
-							If this value is greater than 0, the cluster driver
-							node in particular will be placed on an on-demand
-							instance. If this value is greater than or equal to
-							the current cluster size, all nodes will be placed
-							on on-demand instances. If this value is less than
-							the current cluster size, first_on_demand nodes
-							will be placed on on-demand instances and the
-							remainder will be placed on availability instances.
-							Note that this value does not affect cluster size
-							and cannot be mutated over the lifetime of a
-							cluster.`,
-							Type:     schema.TypeInt,
-							Default:  0,
-							Optional: true,
+						"url": {
+							Description: `URL of the docker image.`,
+							Type:        schema.TypeString,
+							Required:    true,
 						},
-
-						"availability": {
-							Description: `Availability type used for all
-							subsequent nodes past the first_on_demand ones.
-							Note: If first_on_demand is zero, this availability
-							type will be used for the entire cluster.`,
-							Type:     schema.TypeString,
+						"basic_auth": {
+							Description: `Basic authentication for the
+							registry the image is pulled from.`,
+							Type:     schema.TypeList,
 							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"username": {
+										Description: `Name of the user.`,
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"password": {
+										Description: `Password of the user.`,
+										Type:        schema.TypeString,
+										Required:    true,
+										Sensitive:   true,
+									},
+								},
+							},
 						},
+					},
+				},
+			},
+			"aws_attributes":   clusterAWSAttributesSchema(),
+			"azure_attributes": clusterAzureAttributesSchema(),
+			"gcp_attributes":   clusterGCPAttributesSchema(),
+		},
+	}
+}
 
-						"zone_id": {
-							Description: `Identifier for the availability
-							zone/datacenter in which the cluster resides.`,
-							Type:     schema.TypeString,
-							Optional: true,
-						},
+// clusterAWSAttributesSchema is the schema of the aws_attributes of clusters.
+func clusterAWSAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"first_on_demand": {
+					Description: `The first first_on_demand nodes of
+					the cluster will be placed on on-demand instances.
+					If this value is greater than 0, the cluster driver
+					node in particular will be placed on an on-demand
+					instance. If this value is greater than or equal to
+					the current cluster size, all nodes will be placed
+					on on-demand instances. If this value is less than
+					the current cluster size, first_on_demand nodes
+					will be placed on on-demand instances and the
+					remainder will be placed on availability instances.
+					Note that this value does not affect cluster size
+					and cannot be mutated over the lifetime of a
+					cluster.`,
+					Type:     schema.TypeInt,
+					Default:  0,
+					Optional: true,
+				},
 
-						"instance_profile_arn": {
-							Description: `Nodes for this cluster will only be
-							placed on AWS instances with this instance profile.
-							If ommitted, nodes will be placed on instances
-							without an IAM instance profile. The instance
-							profile must have previously been added to the
-							Databricks environment by an account
-							administrator.`,
-							Type:     schema.TypeString,
-							Optional: true,
-						},
+				"availability": {
+					Description: `Availability type used for all
+					subsequent nodes past the first_on_demand ones.
+					Note: If first_on_demand is zero, this availability
+					type will be used for the entire cluster.`,
+					Type:     schema.TypeString,
+					Optional: true,
+				},
 
-						"spot_bid_price_percent": {
-							Description: `The bid price for AWS spot instances,
-							as a percentage of the corresponding instance
-							type’s on-demand price.`,
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  100,
-						},
+				"zone_id": {
+					Description: `Identifier for the availability
+					zone/datacenter in which the cluster resides.`,
+					Type:     schema.TypeString,
+					Optional: true,
+				},
 
-						"ebs_volume_count": {
-							Description: `The number of volumes launched for
-							each instance. You can choose up to 10 volumes.
-							This feature is only enabled for supported node
-							types.`,
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
-						},
+				"instance_profile_arn": {
+					Description: `Nodes for this cluster will only be
+					placed on AWS instances with this instance profile.
+					If ommitted, nodes will be placed on instances
+					without an IAM instance profile. The instance
+					profile must have previously been added to the
+					Databricks environment by an account
+					administrator.`,
+					Type:     schema.TypeString,
+					Optional: true,
+				},
 
-						"ebs_volume_size": {
-							Description: `The size of each EBS volume (in GiB)
-							launched for each instance. For general purpose
-							SSD, this value must be within the range 100 -
-							4096. For throughput optimized HDD, this value must
-							be within the range 500 - 4096.`,
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  500,
-						},
-					},
+				"spot_bid_price_percent": {
+					Description: `The bid price for AWS spot instances,
+					as a percentage of the corresponding instance
+					type’s on-demand price.`,
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  100,
+				},
+
+				"ebs_volume_count": {
+					Description: `The number of volumes launched for
+					each instance. You can choose up to 10 volumes.
+					This feature is only enabled for supported node
+					types.`,
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  0,
+				},
+
+				"ebs_volume_size": {
+					Description: `The size of each EBS volume (in GiB)
+					launched for each instance. For general purpose
+					SSD, this value must be within the range 100 -
+					4096. For throughput optimized HDD, this value must
+					be within the range 500 - 4096.`,
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  500,
 				},
 			},
 		},
 	}
 }
 
-func resourceServerCreate(data *schema.ResourceData, client interface{}) error {
-	awsAttrs := &db.AWSAttributes{}
+// clusterAzureAttributesSchema is the schema of the azure_attributes of clusters.
+func clusterAzureAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: `Attributes of clusters in Azure workspaces.`,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"first_on_demand": {
+					Description: `The first first_on_demand nodes of
+					the cluster will be placed on on-demand instances.
+					This value must be greater than 0 or the cluster
+					driver node will be placed on a spot instance.`,
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  1,
+				},
 
-	// TODO(daniel): clean this up and check type casting better
-	configuredAWSAttrs := data.Get("aws_attributes").([]interface{})
-	for _, m := range configuredAWSAttrs {
-		d := m.(map[string]interface{})
-		awsAttrs.FirstOnDemand = d["first_on_demand"].(int32)
-		awsAttrs.Availability = d["aws_availability"].(db.AWSAvailability)
-		awsAttrs.ZoneID = d["zone_id"].(string)
-		arn := d["instance_profile_arn"].(string)
-		awsAttrs.InstanceProfileARN = &arn
-		pricePercent := d["spot_bid_price_percent"].(int32)
-		awsAttrs.SpotBidPricePercent = &pricePercent
-		volType := d["ebs_volume_type"].(db.EBSVolumeType)
-		awsAttrs.EBSVolumeType = &volType
-		volCount := d["ebs_volume_count"].(int32)
-		awsAttrs.EBSVolumeCount = &volCount
-		volSize := d["ebs_volume_size"].(int32)
-		awsAttrs.EBSVolumeSize = &volSize
+				"availability": {
+					Description: `Availability type used for all
+					subsequent nodes past the first_on_demand ones, one
+					of SPOT_AZURE, ON_DEMAND_AZURE or
+					SPOT_WITH_FALLBACK_AZURE.`,
+					Type:     schema.TypeString,
+					Optional: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"SPOT_AZURE",
+						"ON_DEMAND_AZURE",
+						"SPOT_WITH_FALLBACK_AZURE",
+					}, false),
+				},
+
+				"spot_bid_max_price": {
+					Description: `The max bid price used for Azure
+					spot instances. -1 means the on-demand price.`,
+					Type:     schema.TypeFloat,
+					Optional: true,
+					Default:  -1,
+				},
+			},
+		},
+	}
+}
+
+// clusterGCPAttributesSchema is the schema of the gcp_attributes of clusters.
+func clusterGCPAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: `Attributes of clusters in GCP workspaces.`,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"google_service_account": {
+					Description: `Google service account email
+					address that the cluster uses to authenticate with
+					Google Identity.`,
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+
+				"availability": {
+					Description: `Availability type used for all
+					nodes, one of PREEMPTIBLE_GCP, ON_DEMAND_GCP or
+					PREEMPTIBLE_WITH_FALLBACK_GCP.`,
+					Type:     schema.TypeString,
+					Optional: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"PREEMPTIBLE_GCP",
+						"ON_DEMAND_GCP",
+						"PREEMPTIBLE_WITH_FALLBACK_GCP",
+					}, false),
+				},
+
+				"boot_disk_size": {
+					Description: `Size of the boot disk of each
+					node in GB.`,
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+
+				"zone_id": {
+					Description: `Identifier for the availability
+					zone in which the cluster resides, e.g.
+					us-central1-a.`,
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func resourceServerCreate(data *schema.ResourceData, client interface{}) error {
+	if err := validateClusterNodeType(data); err != nil {
+		return err
 	}
 
 	createReq := &db.ClusterCreateRequest{
@@ -219,49 +430,39 @@ func resourceServerCreate(data *schema.ResourceData, client interface{}) error {
 		SparkVersion:           data.Get("spark_version").(string),
 		NodeTypeID:             data.Get("node_type").(string),
 		DriverNodeTypeID:       data.Get("driver_node_type").(string),
+		InstancePoolID:         data.Get("instance_pool_id").(string),
+		DriverInstancePoolID:   data.Get("driver_instance_pool_id").(string),
+		PolicyID:               data.Get("policy_id").(string),
+		DockerImage:            getClusterDockerImage(data),
 		AutoterminationMinutes: int32(data.Get("autotermination_minutes").(int)),
+		SSHPublicKeys:          getClusterSSHKeys(data),
 		EnableElasticDisk:      data.Get("enable_elastic_disk").(bool),
-		AWSAttributes:          awsAttrs,
-		CustomTags:             []db.ClusterTag{},
-	}
-
-	// either set number or workers or configure autoscaling
-	numWorkers, ok := data.Get("num_workers").(int32)
-	if ok {
-		createReq.NumWorkers = &numWorkers
-	} else {
-		minWorkers, ok := data.Get("min_workers").(int32)
-		if !ok {
-			minWorkers = 0
-		}
+		AWSAttributes: getClusterAWSAttributes(
+			data.Get("aws_attributes").([]interface{}),
+		),
+		AzureAttributes: getClusterAzureAttributes(
+			data.Get("azure_attributes").([]interface{}),
+		),
+		GCPAttributes: getClusterGCPAttributes(
+			data.Get("gcp_attributes").([]interface{}),
+		),
+		SparkEnvVars: map[string]string{},
+	}
 
-		maxWorkers, ok := data.Get("max_workers").(int32)
-		if !ok {
-			maxWorkers = 2
-		}
+	createReq.NumWorkers, createReq.Autoscale = getClusterWorkers(data)
 
-		createReq.Autoscale = &db.Autoscale{
-			Min: minWorkers,
-			Max: maxWorkers,
-		}
+	customTags, err := getClusterTags(data, client)
+	if err != nil {
+		return err
 	}
+	createReq.CustomTags = customTags
 
-	// add tags
-	if tags, ok := data.Get("tags").(map[string]interface{}); ok {
-		for key, val := range tags {
-			valStr, ok := val.(string)
-			if !ok {
-				return fmt.Errorf("Tag value %#v is not a string", val)
-			}
-			createReq.CustomTags = append(
-				createReq.CustomTags,
-				db.ClusterTag{
-					Key:   key,
-					Value: valStr,
-				},
-			)
-		}
+	sparkConf, err := getClusterSparkConf(data)
+	if err != nil {
+		return err
 	}
+	createReq.SparkConf = sparkConf
+
 	if sparkEnv, ok := data.Get("spark_env").(map[string]interface{}); ok {
 		for key, val := range sparkEnv {
 			valStr, ok := val.(string)
@@ -272,7 +473,7 @@ func resourceServerCreate(data *schema.ResourceData, client interface{}) error {
 		}
 	}
 
-	id, err := client.(*db.Client).Cluster().Create(
+	id, err := client.(*providerMeta).client.Cluster().Create(
 		context.Background(),
 		createReq,
 	)
@@ -282,11 +483,23 @@ func resourceServerCreate(data *schema.ResourceData, client interface{}) error {
 
 	data.SetId(id)
 
-	return nil
+	if data.Get("is_pinned").(bool) {
+		err := setClusterPinned(context.Background(), client, id, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return waitForClusterRunning(
+		context.Background(),
+		client,
+		id,
+		data.Timeout(schema.TimeoutCreate),
+	)
 }
 
 func resourceServerRead(data *schema.ResourceData, client interface{}) error {
-	getRes, err := client.(*db.Client).Cluster().Get(
+	getRes, err := client.(*providerMeta).client.Cluster().Get(
 		context.Background(),
 		data.Id(),
 	)
@@ -298,9 +511,35 @@ func resourceServerRead(data *schema.ResourceData, client interface{}) error {
 	data.SetId(getRes.ClusterID)
 	data.Set("cluster_name", getRes.ClusterName)
 	data.Set("spark_version", getRes.SparkVersion)
-	data.Set("node_type", getRes.NodeTypeID)
-	data.Set("driver_node_type", getRes.DriverNodeTypeID)
+	data.Set("instance_pool_id", getRes.InstancePoolID)
+	data.Set("driver_instance_pool_id", getRes.DriverInstancePoolID)
+	// clusters in a pool report the node type of the pool, which is not
+	// something that was configured.
+	if len(getRes.InstancePoolID) == 0 {
+		data.Set("node_type", getRes.NodeTypeID)
+	}
+	if len(getRes.DriverInstancePoolID) == 0 && len(getRes.InstancePoolID) == 0 {
+		data.Set("driver_node_type", getRes.DriverNodeTypeID)
+	}
 	data.Set("enable_elastic_disk", getRes.EnableElasticDisk)
+	data.Set("policy_id", getRes.PolicyID)
+	mode := clusterModeFromSparkConf(getRes.SparkConf)
+	data.Set("cluster_mode", mode)
+	data.Set("spark_conf", withoutClusterModeSettings(
+		getRes.SparkConf,
+		clusterModeAddedSparkConf(mode),
+		data.Get("spark_conf").(map[string]interface{}),
+	))
+	data.Set("tags", withoutClusterModeSettings(
+		withoutDefaultTags(
+			client,
+			getRes.CustomTags,
+			data.Get("tags").(map[string]interface{}),
+		),
+		clusterModeTags[mode],
+		data.Get("tags").(map[string]interface{}),
+	))
+	data.Set("docker_image", flattenClusterDockerImage(data, getRes.DockerImage))
 	data.Set("autotermination_minutes", getRes.AutoterminationMinutes)
 	if getRes.NumWorkers != nil {
 		data.Set("num_workers", getRes.NumWorkers)
@@ -309,69 +548,579 @@ func resourceServerRead(data *schema.ResourceData, client interface{}) error {
 		data.Set("min_workers", getRes.Autoscale.Min)
 		data.Set("max_workers", getRes.Autoscale.Max)
 	}
+	data.Set("is_pinned", len(getRes.PinnedByUserName) > 0)
 
 	return nil
 }
 
 func resourceServerUpdate(data *schema.ResourceData, client interface{}) error {
-	awsAttrs := &db.AWSAttributes{}
-	configuredAWSAttrs := data.Get("aws_attributes").([]interface{})
-	for _, m := range configuredAWSAttrs {
-		d := m.(map[string]interface{})
-		awsAttrs.FirstOnDemand = d["first_on_demand"].(int32)
-		awsAttrs.Availability = d["aws_availability"].(db.AWSAvailability)
-		awsAttrs.ZoneID = d["zone_id"].(string)
-		arn := d["instance_profile_arn"].(string)
-		awsAttrs.InstanceProfileARN = &arn
-		pricePercent := d["spot_bid_price_percent"].(int32)
-		awsAttrs.SpotBidPricePercent = &pricePercent
-		volType := d["ebs_volume_type"].(db.EBSVolumeType)
-		awsAttrs.EBSVolumeType = &volType
-		volCount := d["ebs_volume_count"].(int32)
-		awsAttrs.EBSVolumeCount = &volCount
-		volSize := d["ebs_volume_size"].(int32)
-		awsAttrs.EBSVolumeSize = &volSize
+	ctx := context.Background()
+
+	if err := validateClusterNodeType(data); err != nil {
+		return err
+	}
+
+	if clusterNeedsEdit(data) {
+		edited, err := updateClusterConfig(ctx, data, client)
+		if err != nil {
+			return err
+		}
+		data.Set("edit_pending", !edited)
+	}
+
+	if data.HasChange("is_pinned") {
+		err := setClusterPinned(
+			ctx,
+			client,
+			data.Id(),
+			data.Get("is_pinned").(bool),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateClusterConfig applies the changed configuration to the cluster
+// according to its restart_policy. It reports whether the change was
+// applied or deferred until the cluster is terminated.
+func updateClusterConfig(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+) (bool, error) {
+	// the API only accepts edits of running or terminated clusters
+	getRes, err := waitForClusterSettled(
+		ctx,
+		client,
+		data.Id(),
+		data.Timeout(schema.TimeoutUpdate),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// a full edit restarts a running cluster, while a resize does not.
+	running := getRes.State == db.ClusterStateRunning
+	switch {
+	case running && clusterOnlyWorkersChanged(data):
+		err = resizeCluster(ctx, data, client)
+	case running && data.Get("restart_policy").(string) == clusterFailIfRunning:
+		return false, fmt.Errorf(
+			"cluster %s is running and restart_policy is %s",
+			data.Id(),
+			clusterFailIfRunning,
+		)
+	case running && data.Get("restart_policy").(string) == clusterApplyOnNextStart:
+		return false, nil
+	default:
+		err = editCluster(ctx, data, client)
+	}
+	if err != nil {
+		return false, clusterError(ctx, client, data.Id(), err)
 	}
 
+	// editing a running cluster restarts it, a terminated cluster stays
+	// terminated.
+	getRes, err = client.(*providerMeta).client.Cluster().Get(ctx, data.Id())
+	if err != nil {
+		return false, err
+	}
+	if getRes.State == db.ClusterStateTerminated {
+		return true, nil
+	}
+
+	err = waitForClusterRunning(
+		ctx,
+		client,
+		data.Id(),
+		data.Timeout(schema.TimeoutUpdate),
+	)
+	return err == nil, err
+}
+
+func editCluster(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+) error {
 	editReq := &db.ClusterEditRequest{
 		ClusterID:              data.Id(),
 		ClusterName:            data.Get("cluster_name").(string),
 		SparkVersion:           data.Get("spark_version").(string),
 		NodeTypeID:             data.Get("node_type").(string),
 		DriverNodeTypeID:       data.Get("driver_node_type").(string),
-		AutoterminationMinutes: data.Get("autotermination_minutes").(int32),
-		SSHPublicKeys:          data.Get("ssh_keys").([]string),
+		InstancePoolID:         data.Get("instance_pool_id").(string),
+		DriverInstancePoolID:   data.Get("driver_instance_pool_id").(string),
+		PolicyID:               data.Get("policy_id").(string),
+		DockerImage:            getClusterDockerImage(data),
+		AutoterminationMinutes: int32(data.Get("autotermination_minutes").(int)),
+		SSHPublicKeys:          getClusterSSHKeys(data),
 		EnableElasticDisk:      data.Get("enable_elastic_disk").(bool),
-		AWSAttributes:          awsAttrs,
-	}
-	numWorkers, ok := data.Get("num_workers").(int32)
-	if ok {
-		editReq.NumWorkers = &numWorkers
-	} else {
-		minWorkers, ok := data.Get("min_workers").(int32)
-		if !ok {
-			minWorkers = 0
-		}
+		AWSAttributes: getClusterAWSAttributes(
+			data.Get("aws_attributes").([]interface{}),
+		),
+		AzureAttributes: getClusterAzureAttributes(
+			data.Get("azure_attributes").([]interface{}),
+		),
+		GCPAttributes: getClusterGCPAttributes(
+			data.Get("gcp_attributes").([]interface{}),
+		),
+	}
+	editReq.NumWorkers, editReq.Autoscale = getClusterWorkers(data)
 
-		maxWorkers, ok := data.Get("max_workers").(int32)
-		if !ok {
-			maxWorkers = 2
-		}
+	customTags, err := getClusterTags(data, client)
+	if err != nil {
+		return err
+	}
+	editReq.CustomTags = customTags
 
-		editReq.Autoscale = &db.Autoscale{
-			Min: minWorkers,
-			Max: maxWorkers,
-		}
+	sparkConf, err := getClusterSparkConf(data)
+	if err != nil {
+		return err
 	}
-	return client.(*db.Client).Cluster().Edit(
-		context.Background(),
-		editReq,
-	)
+	editReq.SparkConf = sparkConf
+
+	return client.(*providerMeta).client.Cluster().Edit(ctx, editReq)
+}
+
+func resizeCluster(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+) error {
+	resizeReq := &db.ClusterResizeRequest{
+		ClusterID: data.Id(),
+	}
+	resizeReq.NumWorkers, resizeReq.Autoscale = getClusterWorkers(data)
+
+	return client.(*providerMeta).client.Cluster().Resize(ctx, resizeReq)
 }
 
 func resourceServerDelete(data *schema.ResourceData, client interface{}) error {
-	return client.(*db.Client).Cluster().Delete(
+	return client.(*providerMeta).client.Cluster().Delete(
 		context.Background(),
 		data.Id(),
 	)
 }
+
+// resourceClusterCustomizeDiff validates the cluster configuration against
+// the workspace during plan rather than failing halfway through an apply.
+func resourceClusterCustomizeDiff(diff *schema.ResourceDiff, client interface{}) error {
+	if err := validateClusterMode(diff); err != nil {
+		return err
+	}
+	if err := validateClusterCloud(diff, client, ""); err != nil {
+		return err
+	}
+	if err := validateClusterWorkspace(diff, client, ""); err != nil {
+		return err
+	}
+
+	// plan the edit deferred by apply_on_next_start again
+	if diff.Get("edit_pending").(bool) {
+		return diff.SetNew("edit_pending", false)
+	}
+	return nil
+}
+
+// validateClusterWorkspace checks that the spark_version and node types of
+// the cluster at prefix are supported by the workspace.
+func validateClusterWorkspace(
+	diff *schema.ResourceDiff,
+	client interface{},
+	prefix string,
+) error {
+	dbClient := client.(*providerMeta).client
+	cache := client.(*providerMeta).cache
+
+	versionKey := prefix + "spark_version"
+	if diff.HasChange(versionKey) && diff.NewValueKnown(versionKey) {
+		versions, err := cache.getSparkVersions(dbClient)
+		if err != nil {
+			return err
+		}
+		err = validateSupported(
+			versionKey,
+			diff.Get(versionKey).(string),
+			versions,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{"node_type", "driver_node_type"} {
+		key = prefix + key
+		nodeType := diff.Get(key).(string)
+		if len(nodeType) == 0 || !diff.HasChange(key) || !diff.NewValueKnown(key) {
+			continue
+		}
+		nodeTypes, err := cache.getNodeTypes(dbClient)
+		if err != nil {
+			return err
+		}
+		if err := validateSupported(key, nodeType, nodeTypes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getClusterAWSAttributes(configuredAWSAttrs []interface{}) *db.AWSAttributes {
+	if len(configuredAWSAttrs) == 0 {
+		return nil
+	}
+
+	awsAttrs := &db.AWSAttributes{}
+	for _, m := range configuredAWSAttrs {
+		d := m.(map[string]interface{})
+		awsAttrs.FirstOnDemand = int32(d["first_on_demand"].(int))
+		awsAttrs.Availability = db.AWSAvailability(d["availability"].(string))
+		awsAttrs.ZoneID = d["zone_id"].(string)
+		if arn := d["instance_profile_arn"].(string); len(arn) > 0 {
+			awsAttrs.InstanceProfileARN = &arn
+		}
+		pricePercent := int32(d["spot_bid_price_percent"].(int))
+		awsAttrs.SpotBidPricePercent = &pricePercent
+		volCount := int32(d["ebs_volume_count"].(int))
+		awsAttrs.EBSVolumeCount = &volCount
+		volSize := int32(d["ebs_volume_size"].(int))
+		awsAttrs.EBSVolumeSize = &volSize
+	}
+
+	return awsAttrs
+}
+
+func getClusterAzureAttributes(configuredAzureAttrs []interface{}) *db.AzureAttributes {
+	if len(configuredAzureAttrs) == 0 {
+		return nil
+	}
+
+	azureAttrs := &db.AzureAttributes{}
+	for _, m := range configuredAzureAttrs {
+		d := m.(map[string]interface{})
+		azureAttrs.FirstOnDemand = int32(d["first_on_demand"].(int))
+		azureAttrs.Availability = db.AzureAvailability(d["availability"].(string))
+		azureAttrs.SpotBidMaxPrice = d["spot_bid_max_price"].(float64)
+	}
+
+	return azureAttrs
+}
+
+func getClusterGCPAttributes(configuredGCPAttrs []interface{}) *db.GCPAttributes {
+	if len(configuredGCPAttrs) == 0 {
+		return nil
+	}
+
+	gcpAttrs := &db.GCPAttributes{}
+	for _, m := range configuredGCPAttrs {
+		d := m.(map[string]interface{})
+		gcpAttrs.GoogleServiceAccount = d["google_service_account"].(string)
+		gcpAttrs.Availability = db.GCPAvailability(d["availability"].(string))
+		gcpAttrs.BootDiskSize = int32(d["boot_disk_size"].(int))
+		gcpAttrs.ZoneID = d["zone_id"].(string)
+	}
+
+	return gcpAttrs
+}
+
+// validateClusterCloud checks that only the attributes of the cloud the
+// workspace runs in are set on the cluster at prefix.
+func validateClusterCloud(
+	diff *schema.ResourceDiff,
+	client interface{},
+	prefix string,
+) error {
+	cloud := client.(*providerMeta).config.cloud
+	for attrsCloud, key := range map[string]string{
+		cloudAWS:   "aws_attributes",
+		cloudAzure: "azure_attributes",
+		cloudGCP:   "gcp_attributes",
+	} {
+		key = prefix + key
+		if attrsCloud == cloud {
+			continue
+		}
+		if attrs := diff.Get(key).([]interface{}); len(attrs) > 0 {
+			return fmt.Errorf(
+				"%s can not be set on clusters in %s workspaces",
+				key,
+				cloud,
+			)
+		}
+	}
+	return nil
+}
+
+func getClusterDockerImage(data *schema.ResourceData) *db.DockerImage {
+	configuredImages := data.Get("docker_image").([]interface{})
+	if len(configuredImages) == 0 {
+		return nil
+	}
+
+	dockerImage := &db.DockerImage{}
+	for _, m := range configuredImages {
+		d := m.(map[string]interface{})
+		dockerImage.URL = d["url"].(string)
+		for _, authIface := range d["basic_auth"].([]interface{}) {
+			auth := authIface.(map[string]interface{})
+			dockerImage.BasicAuth = &db.DockerBasicAuth{
+				Username: auth["username"].(string),
+				Password: auth["password"].(string),
+			}
+		}
+	}
+
+	return dockerImage
+}
+
+// flattenClusterDockerImage converts the docker image of a cluster into its
+// terraform representation. The API never returns the registry password, so
+// the configured one is kept.
+func flattenClusterDockerImage(
+	data *schema.ResourceData,
+	dockerImage *db.DockerImage,
+) []interface{} {
+	if dockerImage == nil {
+		return []interface{}{}
+	}
+
+	image := map[string]interface{}{
+		"url": dockerImage.URL,
+	}
+	if dockerImage.BasicAuth != nil {
+		password := ""
+		if configured := getClusterDockerImage(data); configured != nil &&
+			configured.BasicAuth != nil {
+			password = configured.BasicAuth.Password
+		}
+		image["basic_auth"] = []interface{}{
+			map[string]interface{}{
+				"username": dockerImage.BasicAuth.Username,
+				"password": password,
+			},
+		}
+	}
+
+	return []interface{}{image}
+}
+
+// clusterNeedsEdit reports whether anything but the settings that are not
+// part of the cluster configuration has changed.
+func clusterNeedsEdit(data *schema.ResourceData) bool {
+	for key := range resourceCluster().Schema {
+		if key != "restart_policy" && key != "is_pinned" && data.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterWorkersKeys are the attributes that can be changed with a resize.
+var clusterWorkersKeys = map[string]bool{
+	"num_workers": true,
+	"min_workers": true,
+	"max_workers": true,
+}
+
+// clusterOnlyWorkersChanged reports whether the number of workers is the
+// only cluster configuration that has changed.
+func clusterOnlyWorkersChanged(data *schema.ResourceData) bool {
+	workersChanged := false
+	for key := range resourceCluster().Schema {
+		if !data.HasChange(key) {
+			continue
+		}
+		switch {
+		case clusterWorkersKeys[key]:
+			workersChanged = true
+		case key == "is_pinned" || key == "restart_policy":
+			// not part of the cluster configuration
+		default:
+			return false
+		}
+	}
+	return workersChanged
+}
+
+// getClusterWorkers returns either a fixed number of workers or the
+// autoscaling bounds of the cluster.
+func getClusterWorkers(data *schema.ResourceData) (*int32, *db.Autoscale) {
+	if data.Get("cluster_mode").(string) == clusterModeSingleNode {
+		numWorkers := int32(0)
+		return &numWorkers, nil
+	}
+	if numWorkers := int32(data.Get("num_workers").(int)); numWorkers > 0 {
+		return &numWorkers, nil
+	}
+
+	return nil, &db.Autoscale{
+		Min: int32(data.Get("min_workers").(int)),
+		Max: int32(data.Get("max_workers").(int)),
+	}
+}
+
+func getClusterSSHKeys(data *schema.ResourceData) []string {
+	keys := []string{}
+	for _, key := range data.Get("ssh_keys").([]interface{}) {
+		keys = append(keys, key.(string))
+	}
+	return keys
+}
+
+// validateClusterNodeType checks that the cluster either has a node type or
+// gets its nodes from an instance pool.
+func validateClusterNodeType(data *schema.ResourceData) error {
+	nodeType := data.Get("node_type").(string)
+	poolID := data.Get("instance_pool_id").(string)
+	if len(nodeType) == 0 && len(poolID) == 0 {
+		return fmt.Errorf("one of node_type or instance_pool_id must be set")
+	}
+	return nil
+}
+
+// waitForClusterRunning waits for a cluster to start. If the cluster
+// terminates instead, the reason and the events leading up to it are
+// returned as an error.
+func waitForClusterRunning(
+	ctx context.Context,
+	client interface{},
+	clusterID string,
+	timeout time.Duration,
+) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		getRes, err := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		switch getRes.State {
+		case db.ClusterStateRunning:
+			return nil
+		case db.ClusterStateTerminating,
+			db.ClusterStateTerminated,
+			db.ClusterStateError:
+			return resource.NonRetryableError(clusterError(
+				ctx,
+				client,
+				clusterID,
+				fmt.Errorf(
+					"cluster %s is %s: %s",
+					clusterID,
+					getRes.State,
+					getRes.StateMessage,
+				),
+			))
+		}
+		return resource.RetryableError(fmt.Errorf(
+			"cluster %s is %s", clusterID, getRes.State,
+		))
+	})
+}
+
+// waitForClusterSettled waits for a cluster to be either running or
+// terminated, the states in which it can be edited.
+func waitForClusterSettled(
+	ctx context.Context,
+	client interface{},
+	clusterID string,
+	timeout time.Duration,
+) (*db.ClusterInfo, error) {
+	var cluster *db.ClusterInfo
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		getRes, err := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		switch getRes.State {
+		case db.ClusterStateRunning, db.ClusterStateTerminated:
+			cluster = getRes
+			return nil
+		case db.ClusterStateError:
+			return resource.NonRetryableError(clusterError(
+				ctx,
+				client,
+				clusterID,
+				fmt.Errorf(
+					"cluster %s is %s: %s",
+					clusterID,
+					getRes.State,
+					getRes.StateMessage,
+				),
+			))
+		}
+		return resource.RetryableError(fmt.Errorf(
+			"cluster %s is %s", clusterID, getRes.State,
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+// clusterError adds the termination reason and most recent events of a
+// cluster to err, as the API errors alone rarely explain what went wrong.
+func clusterError(
+	ctx context.Context,
+	client interface{},
+	clusterID string,
+	err error,
+) error {
+	msgs := []string{err.Error()}
+
+	getRes, getErr := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+	if getErr == nil && getRes.TerminationReason != nil {
+		msgs = append(msgs, fmt.Sprintf(
+			"termination reason: %s %v",
+			getRes.TerminationReason.Code,
+			getRes.TerminationReason.Parameters,
+		))
+	}
+
+	events, eventsErr := client.(*providerMeta).client.Cluster().Events(
+		ctx,
+		&db.ClusterEventsRequest{
+			ClusterID: clusterID,
+			Order:     db.ListOrderDesc,
+			Limit:     5,
+		},
+	)
+	if eventsErr == nil {
+		for _, event := range events {
+			msg := fmt.Sprintf(
+				"event %s at %s",
+				event.Type,
+				time.Unix(0, event.Timestamp*int64(time.Millisecond)).Format(time.RFC3339),
+			)
+			if event.Details != nil && event.Details.Reason != nil {
+				msg += fmt.Sprintf(
+					": %s %v",
+					event.Details.Reason.Code,
+					event.Details.Reason.Parameters,
+				)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return fmt.Errorf("%s", strings.Join(msgs, "\n  "))
+}
+
+// setClusterPinned pins or unpins a cluster so that it is kept (or not) past
+// the 30 day cleanup of terminated clusters.
+func setClusterPinned(
+	ctx context.Context,
+	client interface{},
+	clusterID string,
+	pinned bool,
+) error {
+	if pinned {
+		return client.(*providerMeta).client.Cluster().Pin(ctx, clusterID)
+	}
+	return client.(*providerMeta).client.Cluster().Unpin(ctx, clusterID)
+}
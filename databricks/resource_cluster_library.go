@@ -0,0 +1,268 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+	"github.com/mitchellh/hashstructure"
+)
+
+func resourceClusterLibrary() *schema.Resource {
+	libSchema := librarySchema()
+	// there is no way to update an installed library, only to uninstall it
+	// and install a new one.
+	for key, s := range libSchema {
+		s.ForceNew = true
+		for other := range libSchema {
+			if other != key {
+				s.ConflictsWith = append(s.ConflictsWith, other)
+			}
+		}
+	}
+	libSchema["cluster_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: `Id of the cluster the library is installed on.`,
+		Required:    true,
+		ForceNew:    true,
+	}
+	libSchema["restart_on_uninstall"] = &schema.Schema{
+		Type: schema.TypeBool,
+		Description: `Libraries are only removed from a cluster once it has
+		been restarted. If set a running cluster is restarted after the
+		library is uninstalled.`,
+		Optional: true,
+		Default:  false,
+	}
+	libSchema["status"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: `Installation status of the library on the cluster.`,
+		Computed:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceClusterLibraryCreate,
+		Read:   resourceClusterLibraryRead,
+		Update: resourceClusterLibraryUpdate,
+		Delete: resourceClusterLibraryDelete,
+		// ConflictsWith rules out more than one kind of library, the
+		// CustomizeDiff makes sure there is one.
+		CustomizeDiff: resourceClusterLibraryCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: libSchema,
+	}
+}
+
+func resourceClusterLibraryCreate(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+	clusterID := data.Get("cluster_id").(string)
+	library := clusterLibraryFromRD(data)
+
+	err := client.(*providerMeta).client.Libraries().Install(
+		ctx,
+		clusterID,
+		[]db.Library{library},
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := hashstructure.Hash(library, nil)
+	if err != nil {
+		return err
+	}
+	data.SetId(fmt.Sprintf("%s/%d", clusterID, id))
+
+	// libraries on a terminated cluster are only installed once the cluster
+	// is started again, so there is nothing to wait for. Other clusters
+	// install the library once they are running.
+	cluster, err := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	switch cluster.State {
+	case db.ClusterStateTerminated, db.ClusterStateTerminating:
+		return resourceClusterLibraryRead(data, client)
+	case db.ClusterStateRunning:
+	default:
+		err := waitForClusterRunning(
+			ctx,
+			client,
+			clusterID,
+			data.Timeout(schema.TimeoutCreate),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = resource.Retry(
+		data.Timeout(schema.TimeoutCreate),
+		func() *resource.RetryError {
+			status, err := clusterLibraryStatus(ctx, client, clusterID, library)
+			if err != nil {
+				return resource.NonRetryableError(err)
+			}
+			if status == nil {
+				return resource.RetryableError(fmt.Errorf(
+					"library is not yet listed on cluster %s", clusterID,
+				))
+			}
+			switch status.Status {
+			case db.LibraryInstallStatusInstalled:
+				return nil
+			case db.LibraryInstallStatusFailed:
+				return resource.NonRetryableError(fmt.Errorf(
+					"library failed to install on cluster %s: %s",
+					clusterID,
+					strings.Join(status.Messages, "; "),
+				))
+			}
+			return resource.RetryableError(fmt.Errorf(
+				"library status on cluster %s is %s", clusterID, status.Status,
+			))
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return resourceClusterLibraryRead(data, client)
+}
+
+func resourceClusterLibraryRead(data *schema.ResourceData, client interface{}) error {
+	status, err := clusterLibraryStatus(
+		context.Background(),
+		client,
+		data.Get("cluster_id").(string),
+		clusterLibraryFromRD(data),
+	)
+	if isNotFound(err) {
+		// the cluster was deleted outside of terraform
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		// the library was removed outside of terraform
+		data.SetId("")
+		return nil
+	}
+	data.Set("status", string(status.Status))
+
+	return nil
+}
+
+func resourceClusterLibraryUpdate(data *schema.ResourceData, client interface{}) error {
+	// everything but restart_on_uninstall forces a new resource, which is
+	// only used on delete.
+	return nil
+}
+
+func resourceClusterLibraryDelete(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+	clusterID := data.Get("cluster_id").(string)
+
+	err := client.(*providerMeta).client.Libraries().Uninstall(
+		ctx,
+		clusterID,
+		[]db.Library{clusterLibraryFromRD(data)},
+	)
+	if isNotFound(err) {
+		// the library went away with the cluster
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !data.Get("restart_on_uninstall").(bool) {
+		return nil
+	}
+	// a cluster that is not running drops the library the next time it
+	// starts, and can't be restarted anyway
+	cluster, err := client.(*providerMeta).client.Cluster().Get(ctx, clusterID)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cluster.State != db.ClusterStateRunning {
+		return nil
+	}
+	return client.(*providerMeta).client.Cluster().Restart(ctx, clusterID)
+}
+
+// resourceClusterLibraryCustomizeDiff checks that one kind of library is set
+// during plan, as an empty library is only rejected by the API.
+func resourceClusterLibraryCustomizeDiff(diff *schema.ResourceDiff, client interface{}) error {
+	for key := range librarySchema() {
+		if !diff.NewValueKnown(key) {
+			return nil
+		}
+		switch val := diff.Get(key).(type) {
+		case string:
+			if len(val) > 0 {
+				return nil
+			}
+		case *schema.Set:
+			if val.Len() > 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("one of jar, egg, whl, pypi, maven or cran must be set")
+}
+
+func clusterLibraryFromRD(data *schema.ResourceData) db.Library {
+	lib := map[string]interface{}{}
+	for key := range librarySchema() {
+		lib[key] = data.Get(key)
+	}
+	return libraryFromMap(lib)
+}
+
+// clusterLibraryStatus returns the status of library on the cluster, or nil
+// if the library is not installed on the cluster.
+func clusterLibraryStatus(
+	ctx context.Context,
+	client interface{},
+	clusterID string,
+	library db.Library,
+) (*db.LibraryFullStatus, error) {
+	want, err := hashstructure.Hash(library, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := client.(*providerMeta).client.Libraries().ClusterStatus(
+		ctx,
+		clusterID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i, status := range statuses {
+		if status.Library == nil {
+			continue
+		}
+		got, err := hashstructure.Hash(*status.Library, nil)
+		if err != nil {
+			return nil, err
+		}
+		if got == want {
+			return &statuses[i], nil
+		}
+	}
+
+	return nil, nil
+}
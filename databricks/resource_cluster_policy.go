@@ -0,0 +1,132 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+func resourceClusterPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceClusterPolicyCreate,
+		Read:   resourceClusterPolicyRead,
+		Update: resourceClusterPolicyUpdate,
+		Delete: resourceClusterPolicyDelete,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Cluster policy name. This must be unique.`,
+				Required:    true,
+			},
+			"definition": &schema.Schema{
+				Type: schema.TypeString,
+				Description: `Policy definition document expressed in the
+				Databricks policy definition language, as a JSON string.`,
+				Required: true,
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					if _, err := normalizeJSON(i.(string)); err != nil {
+						return []string{}, []error{fmt.Errorf(
+							"%s is not valid JSON: %s", s, err),
+						}
+					}
+					return []string{}, []error{}
+				},
+				StateFunc: func(i interface{}) string {
+					// validation has already happened, so an invalid
+					// definition is stored as is.
+					normalized, err := normalizeJSON(i.(string))
+					if err != nil {
+						return i.(string)
+					}
+					return normalized
+				},
+			},
+		},
+	}
+}
+
+func resourceClusterPolicyCreate(data *schema.ResourceData, client interface{}) error {
+	definition, err := normalizeJSON(data.Get("definition").(string))
+	if err != nil {
+		return err
+	}
+
+	id, err := client.(*providerMeta).client.ClusterPolicies().Create(
+		context.Background(),
+		&db.ClusterPolicy{
+			Name:       data.Get("name").(string),
+			Definition: definition,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(id)
+
+	return resourceClusterPolicyRead(data, client)
+}
+
+func resourceClusterPolicyRead(data *schema.ResourceData, client interface{}) error {
+	policy, err := client.(*providerMeta).client.ClusterPolicies().Get(
+		context.Background(),
+		data.Id(),
+	)
+	if err != nil {
+		return err
+	}
+
+	definition, err := normalizeJSON(policy.Definition)
+	if err != nil {
+		return err
+	}
+	data.Set("name", policy.Name)
+	data.Set("definition", definition)
+
+	return nil
+}
+
+func resourceClusterPolicyUpdate(data *schema.ResourceData, client interface{}) error {
+	definition, err := normalizeJSON(data.Get("definition").(string))
+	if err != nil {
+		return err
+	}
+
+	err = client.(*providerMeta).client.ClusterPolicies().Edit(
+		context.Background(),
+		&db.ClusterPolicy{
+			PolicyID:   data.Id(),
+			Name:       data.Get("name").(string),
+			Definition: definition,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return resourceClusterPolicyRead(data, client)
+}
+
+func resourceClusterPolicyDelete(data *schema.ResourceData, client interface{}) error {
+	return client.(*providerMeta).client.ClusterPolicies().Delete(
+		context.Background(),
+		data.Id(),
+	)
+}
+
+// normalizeJSON re-encodes a JSON document so that whitespace and key order
+// do not matter when comparing documents.
+func normalizeJSON(doc string) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
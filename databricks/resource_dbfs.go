@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	db "github.com/medivo/databricks-go"
 )
 
 func resourceDBFS() *schema.Resource {
@@ -51,7 +50,7 @@ func resourceDBFSCreate(data *schema.ResourceData, client interface{}) error {
 
 	// if there is a source then it's not a directory...
 	if !ok || len(src) == 0 {
-		err := client.(*db.Client).DBFS().Mkdirs(
+		err := client.(*providerMeta).client.DBFS().Mkdirs(
 			ctx,
 			dbfsPath,
 		)
@@ -64,7 +63,7 @@ func resourceDBFSCreate(data *schema.ResourceData, client interface{}) error {
 	}
 
 	// do a mkdir -p :)
-	client.(*db.Client).DBFS().Mkdirs(
+	client.(*providerMeta).client.DBFS().Mkdirs(
 		ctx,
 		path.Dir(dbfsPath),
 	)
@@ -79,7 +78,7 @@ func resourceDBFSCreate(data *schema.ResourceData, client interface{}) error {
 }
 
 func resourceDBFSRead(data *schema.ResourceData, client interface{}) error {
-	isDir, fileSize, err := client.(*db.Client).DBFS().GetStatus(
+	isDir, fileSize, err := client.(*providerMeta).client.DBFS().GetStatus(
 		context.Background(),
 		data.Id(),
 	)
@@ -96,7 +95,7 @@ func resourceDBFSRead(data *schema.ResourceData, client interface{}) error {
 
 func resourceDBFSUpdate(data *schema.ResourceData, client interface{}) error {
 	// if it is a directory then it's a noop
-	isDir, _, err := client.(*db.Client).DBFS().GetStatus(
+	isDir, _, err := client.(*providerMeta).client.DBFS().GetStatus(
 		context.Background(),
 		data.Id(),
 	)
@@ -109,7 +108,7 @@ func resourceDBFSUpdate(data *schema.ResourceData, client interface{}) error {
 
 	// for updating a file it doesn't really make sense to do diffs, so we just
 	// end up replacing it?
-	err = client.(*db.Client).DBFS().Delete(
+	err = client.(*providerMeta).client.DBFS().Delete(
 		context.Background(),
 		data.Id(),
 		false,
@@ -128,7 +127,7 @@ func resourceDBFSUpdate(data *schema.ResourceData, client interface{}) error {
 }
 
 func resourceDBFSDelete(data *schema.ResourceData, client interface{}) error {
-	return client.(*db.Client).DBFS().Delete(
+	return client.(*providerMeta).client.DBFS().Delete(
 		context.Background(),
 		data.Id(),
 		false,
@@ -141,7 +140,7 @@ func dbfsUploadFile(
 	data *schema.ResourceData,
 	dbfsPath, source string,
 ) error {
-	handle, err := client.(*db.Client).DBFS().Create(
+	handle, err := client.(*providerMeta).client.DBFS().Create(
 		ctx,
 		dbfsPath,
 		true,
@@ -194,7 +193,7 @@ func dbfsUploadFile(
 		if err != nil {
 			return err
 		}
-		err = client.(*db.Client).DBFS().AddBlock(
+		err = client.(*providerMeta).client.DBFS().AddBlock(
 			ctx,
 			handle,
 			buf,
@@ -214,5 +213,5 @@ func dbfsUploadFile(
 	}
 	data.SetId(dbfsPath)
 
-	return client.(*db.Client).DBFS().Close(ctx, handle)
+	return client.(*providerMeta).client.DBFS().Close(ctx, handle)
 }
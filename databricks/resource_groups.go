@@ -62,7 +62,7 @@ func resourceGroupsCreate(data *schema.ResourceData, client interface{}) error {
 	groups := groupsFromRD(data)
 	err := createGroups(
 		data,
-		client.(*db.Client).Groups(),
+		client.(*providerMeta).client.Groups(),
 	)
 	if err != nil {
 		return err
@@ -80,7 +80,7 @@ func resourceGroupsCreate(data *schema.ResourceData, client interface{}) error {
 func resourceGroupsRead(data *schema.ResourceData, client interface{}) error {
 	ctx := context.Background()
 	groups := groupsFromRD(data)
-	groupsService := client.(*db.Client).Groups()
+	groupsService := client.(*providerMeta).client.Groups()
 
 	for group := range groups {
 		principalMembers, err := groupsService.Members(ctx, group)
@@ -106,7 +106,7 @@ func resourceGroupsUpdate(data *schema.ResourceData, client interface{}) error {
 	// TODO(daniel): do actual diffs?
 	groups := groupsFromRD(data)
 	err := deleteGroups(
-		client.(*db.Client).Groups(),
+		client.(*providerMeta).client.Groups(),
 		groups,
 	)
 	if err != nil {
@@ -123,7 +123,7 @@ func resourceGroupsUpdate(data *schema.ResourceData, client interface{}) error {
 }
 func resourceGroupsDelete(data *schema.ResourceData, client interface{}) error {
 	return deleteGroups(
-		client.(*db.Client).Groups(),
+		client.(*providerMeta).client.Groups(),
 		groupsFromRD(data),
 	)
 }
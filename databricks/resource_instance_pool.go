@@ -0,0 +1,296 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+func resourceInstancePool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceInstancePoolCreate,
+		Read:   resourceInstancePoolRead,
+		Update: resourceInstancePoolUpdate,
+		Delete: resourceInstancePoolDelete,
+		Schema: map[string]*schema.Schema{
+			"instance_pool_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				Description: `The name of the instance pool. This is required
+				for create and edit operations. It must be unique, non-empty,
+				and less than 100 characters.`,
+			},
+			"node_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				Description: `The node type for the instances in the pool. All
+				clusters attached to the pool inherit this node type and the
+				pool’s idle instances are allocated based on this type.`,
+				ForceNew: true,
+			},
+			"min_idle_instances": &schema.Schema{
+				Type: schema.TypeInt,
+				Description: `The minimum number of idle instances maintained
+				by the pool. This is in addition to any instances in use by
+				active clusters.`,
+				Default:  0,
+				Optional: true,
+			},
+			"max_capacity": &schema.Schema{
+				Type: schema.TypeInt,
+				Description: `The maximum number of instances the pool can
+				contain, including both idle instances and ones in use by
+				clusters. Once the maximum capacity is reached, you cannot
+				create new clusters from the pool.`,
+				Optional: true,
+			},
+			"idle_instance_autotermination_minutes": &schema.Schema{
+				Type: schema.TypeInt,
+				Description: `The number of minutes that idle instances in
+				excess of the min_idle_instances are maintained by the pool
+				before being terminated.`,
+				Required: true,
+			},
+			"enable_elastic_disk": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Autoscaling Local Storage: when enabled, the
+				instances in the pool dynamically acquire additional disk
+				space when they are running low on disk space.`,
+				Default:  false,
+				Optional: true,
+				ForceNew: true,
+			},
+			"preloaded_spark_versions": &schema.Schema{
+				Type: schema.TypeList,
+				Description: `A list with the runtime version the pool
+				installs on each instance. Pool clusters that use a preloaded
+				runtime version start faster as they do not have to wait for
+				the image to download.`,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"custom_tags": {
+				Type: schema.TypeMap,
+				Description: `Additional tags for instance pool resources.
+				Databricks tags all pool resources with these tags in addition
+				to default_tags.`,
+				Optional: true,
+				ForceNew: true,
+			},
+			"aws_attributes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability": {
+							Description: `Availability type used for all
+							instances in the pool. Only ON_DEMAND and SPOT are
+							supported.`,
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"zone_id": {
+							Description: `Identifier for the availability
+							zone/datacenter in which the instances reside.`,
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"spot_bid_price_percent": {
+							Description: `The max price for AWS spot
+							instances, as a percentage of the corresponding
+							instance type’s on-demand price.`,
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  100,
+						},
+					},
+				},
+			},
+			"default_tags": {
+				Type:        schema.TypeMap,
+				Description: `Tags that are added by Databricks.`,
+				Computed:    true,
+			},
+			"state": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Current state of the instance pool.`,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceInstancePoolCreate(data *schema.ResourceData, client interface{}) error {
+	createReq := &db.InstancePoolCreateRequest{
+		InstancePoolName:                   data.Get("instance_pool_name").(string),
+		NodeTypeID:                         data.Get("node_type").(string),
+		MinIdleInstances:                   int32(data.Get("min_idle_instances").(int)),
+		IdleInstanceAutoterminationMinutes: int32(data.Get("idle_instance_autotermination_minutes").(int)),
+		EnableElasticDisk:                  data.Get("enable_elastic_disk").(bool),
+		AWSAttributes:                      getInstancePoolAWSAttributes(data),
+		CustomTags:                         []db.ClusterTag{},
+	}
+
+	if maxCapacity, ok := data.GetOk("max_capacity"); ok {
+		capacity := int32(maxCapacity.(int))
+		createReq.MaxCapacity = &capacity
+	}
+
+	for _, version := range data.Get("preloaded_spark_versions").([]interface{}) {
+		createReq.PreloadedSparkVersions = append(
+			createReq.PreloadedSparkVersions,
+			version.(string),
+		)
+	}
+
+	for key, val := range data.Get("custom_tags").(map[string]interface{}) {
+		valStr, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("Tag value %#v is not a string", val)
+		}
+		createReq.CustomTags = append(
+			createReq.CustomTags,
+			db.ClusterTag{
+				Key:   key,
+				Value: valStr,
+			},
+		)
+	}
+
+	id, err := client.(*providerMeta).client.InstancePools().Create(
+		context.Background(),
+		createReq,
+	)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(id)
+
+	return resourceInstancePoolRead(data, client)
+}
+
+func resourceInstancePoolRead(data *schema.ResourceData, client interface{}) error {
+	pool, err := client.(*providerMeta).client.InstancePools().Get(
+		context.Background(),
+		data.Id(),
+	)
+	if err != nil {
+		return err
+	}
+
+	data.Set("instance_pool_name", pool.InstancePoolName)
+	data.Set("node_type", pool.NodeTypeID)
+	data.Set("min_idle_instances", int(pool.MinIdleInstances))
+	if pool.MaxCapacity != nil {
+		data.Set("max_capacity", int(*pool.MaxCapacity))
+	}
+	data.Set(
+		"idle_instance_autotermination_minutes",
+		int(pool.IdleInstanceAutoterminationMinutes),
+	)
+	data.Set("enable_elastic_disk", pool.EnableElasticDisk)
+	data.Set("preloaded_spark_versions", pool.PreloadedSparkVersions)
+	data.Set("state", string(pool.State))
+
+	customTags := map[string]string{}
+	for _, tag := range pool.CustomTags {
+		customTags[tag.Key] = tag.Value
+	}
+	if err := data.Set("custom_tags", customTags); err != nil {
+		return err
+	}
+	// the API fills in aws_attributes on AWS workspaces, which would replace
+	// pools that don't configure them.
+	if len(data.Get("aws_attributes").([]interface{})) > 0 {
+		err := data.Set(
+			"aws_attributes",
+			flattenInstancePoolAWSAttributes(pool.AWSAttributes),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	defaultTags := map[string]string{}
+	for _, tag := range pool.DefaultTags {
+		defaultTags[tag.Key] = tag.Value
+	}
+	data.Set("default_tags", defaultTags)
+
+	return nil
+}
+
+func resourceInstancePoolUpdate(data *schema.ResourceData, client interface{}) error {
+	editReq := &db.InstancePoolEditRequest{
+		InstancePoolID:                     data.Id(),
+		InstancePoolName:                   data.Get("instance_pool_name").(string),
+		NodeTypeID:                         data.Get("node_type").(string),
+		MinIdleInstances:                   int32(data.Get("min_idle_instances").(int)),
+		IdleInstanceAutoterminationMinutes: int32(data.Get("idle_instance_autotermination_minutes").(int)),
+	}
+
+	if maxCapacity, ok := data.GetOk("max_capacity"); ok {
+		capacity := int32(maxCapacity.(int))
+		editReq.MaxCapacity = &capacity
+	}
+
+	err := client.(*providerMeta).client.InstancePools().Edit(
+		context.Background(),
+		editReq,
+	)
+	if err != nil {
+		return err
+	}
+
+	return resourceInstancePoolRead(data, client)
+}
+
+func resourceInstancePoolDelete(data *schema.ResourceData, client interface{}) error {
+	return client.(*providerMeta).client.InstancePools().Delete(
+		context.Background(),
+		data.Id(),
+	)
+}
+
+func getInstancePoolAWSAttributes(data *schema.ResourceData) *db.InstancePoolAWSAttributes {
+	configuredAWSAttrs := data.Get("aws_attributes").([]interface{})
+	if len(configuredAWSAttrs) == 0 {
+		return nil
+	}
+
+	awsAttrs := &db.InstancePoolAWSAttributes{}
+	for _, m := range configuredAWSAttrs {
+		d := m.(map[string]interface{})
+		awsAttrs.Availability = db.AWSAvailability(d["availability"].(string))
+		awsAttrs.ZoneID = d["zone_id"].(string)
+		pricePercent := int32(d["spot_bid_price_percent"].(int))
+		awsAttrs.SpotBidPricePercent = &pricePercent
+	}
+
+	return awsAttrs
+}
+
+func flattenInstancePoolAWSAttributes(awsAttrs *db.InstancePoolAWSAttributes) []interface{} {
+	if awsAttrs == nil {
+		return []interface{}{}
+	}
+	attrs := map[string]interface{}{
+		"availability": string(awsAttrs.Availability),
+		"zone_id":      awsAttrs.ZoneID,
+	}
+	if awsAttrs.SpotBidPricePercent != nil {
+		attrs["spot_bid_price_percent"] = int(*awsAttrs.SpotBidPricePercent)
+	}
+	return []interface{}{attrs}
+}
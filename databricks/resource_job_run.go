@@ -0,0 +1,281 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+func resourceJobRun() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceJobRunCreate,
+		Read:          resourceJobRunRead,
+		Delete:        resourceJobRunDelete,
+		CustomizeDiff: resourceJobRunCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"run_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `An optional name for the run.`,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"existing_cluster_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   `The id of an existing cluster to run on. Conflicts with new_cluster.`,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"new_cluster"},
+			},
+			"new_cluster": forceNew(
+				newClusterSchema(),
+				"existing_cluster_id",
+			),
+			"timeout_seconds": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Run timeout in seconds, the run is not timed out if unset.`,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"libraries": forceNew(&schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: librarySchema(),
+				},
+			}),
+			"notebook_task": forceNew(
+				notebookTaskSchema(),
+				"spark_python_task",
+				"spark_submit_task",
+				"spark_jar_task",
+			),
+			"spark_python_task": forceNew(
+				sparkPythonTaskSchema(),
+				"notebook_task",
+				"spark_submit_task",
+				"spark_jar_task",
+			),
+			"spark_submit_task": forceNew(
+				sparkSubmitTaskSchema(),
+				"notebook_task",
+				"spark_python_task",
+				"spark_jar_task",
+			),
+			"spark_jar_task": forceNew(
+				sparkJarTaskSchema(),
+				"notebook_task",
+				"spark_python_task",
+				"spark_submit_task",
+			),
+			"run_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Canonical identifier of the run.`,
+				Computed:    true,
+			},
+			"run_page_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `URL of the run in the workspace.`,
+				Computed:    true,
+			},
+			"life_cycle_state": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Life cycle state of the run.`,
+				Computed:    true,
+			},
+			"result_state": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Result state of the run, only set once it has finished.`,
+				Computed:    true,
+			},
+			"state_message": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Message describing the state of the run.`,
+				Computed:    true,
+			},
+			"notebook_output": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Value passed to dbutils.notebook.exit() by the notebook task.`,
+				Computed:    true,
+			},
+			"notebook_output_truncated": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Whether notebook_output was truncated.`,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceJobRunCreate(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+
+	newCluster, err := getNewCluster(
+		data.Get("new_cluster").([]interface{}),
+		client,
+	)
+	if err != nil {
+		return err
+	}
+
+	submitReq := &db.RunSubmitRequest{
+		RunName:         data.Get("run_name").(string),
+		NewCluster:      newCluster,
+		NotebookTask:    getNotebookTask(data.Get("notebook_task").(*schema.Set)),
+		SparkJarTask:    getSparkJarTask(data.Get("spark_jar_task").(*schema.Set)),
+		SparkPythonTask: getSparkPythonTask(data.Get("spark_python_task").(*schema.Set)),
+		SparkSubmitTask: getSparkSubmitTask(data.Get("spark_submit_task").(*schema.Set)),
+		Libraries:       getLibraries(data.Get("libraries").(*schema.Set)),
+	}
+	if clusterID := data.Get("existing_cluster_id").(string); len(clusterID) > 0 {
+		submitReq.ExistingClusterID = &clusterID
+	}
+	if submitReq.ExistingClusterID == nil && submitReq.NewCluster == nil {
+		return fmt.Errorf("one of existing_cluster_id or new_cluster must be set")
+	}
+	if to, ok := data.GetOk("timeout_seconds"); ok {
+		timeout := int32(to.(int))
+		submitReq.TimeoutSeconds = &timeout
+	}
+
+	runID, err := client.(*providerMeta).client.Jobs().RunsSubmit(ctx, submitReq)
+	if err != nil {
+		return err
+	}
+	data.SetId(strconv.FormatInt(runID, 10))
+
+	run, err := waitForRun(
+		ctx,
+		client,
+		runID,
+		data.Timeout(schema.TimeoutCreate),
+	)
+	if err != nil {
+		return err
+	}
+	if err := setJobRun(ctx, data, client, run); err != nil {
+		return err
+	}
+
+	return runError(run)
+}
+
+func resourceJobRunRead(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+	runID, err := strconv.ParseInt(data.Id(), 10, 64)
+	if err != nil {
+		return err
+	}
+	run, err := client.(*providerMeta).client.Jobs().GetRun(ctx, runID)
+	if isNotFound(err) {
+		// runs are removed from the history of the workspace after a while
+		data.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return setJobRun(ctx, data, client, run)
+}
+
+func resourceJobRunDelete(data *schema.ResourceData, client interface{}) error {
+	ctx := context.Background()
+	runID, err := strconv.ParseInt(data.Id(), 10, 64)
+	if err != nil {
+		return err
+	}
+	run, err := client.(*providerMeta).client.Jobs().GetRun(ctx, runID)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// finished runs are kept in the run history of the workspace
+	if runIsTerminal(run) {
+		return nil
+	}
+
+	err = client.(*providerMeta).client.Jobs().CancelRun(ctx, runID)
+	if err == nil {
+		return nil
+	}
+	// the run may have finished since it was fetched
+	run, getErr := client.(*providerMeta).client.Jobs().GetRun(ctx, runID)
+	if isNotFound(getErr) || (getErr == nil && runIsTerminal(run)) {
+		return nil
+	}
+	return err
+}
+
+// resourceJobRunCustomizeDiff checks that the run has a valid cluster and
+// exactly one task before it is submitted.
+func resourceJobRunCustomizeDiff(diff *schema.ResourceDiff, client interface{}) error {
+	hasCluster := len(diff.Get("existing_cluster_id").(string)) > 0 ||
+		!diff.NewValueKnown("existing_cluster_id") ||
+		len(diff.Get("new_cluster").([]interface{})) > 0
+	if !hasCluster {
+		return fmt.Errorf("one of existing_cluster_id or new_cluster must be set")
+	}
+	if err := validateNewCluster(diff, client, ""); err != nil {
+		return err
+	}
+
+	task := db.JobTask{}
+	if diff.Get("notebook_task").(*schema.Set).Len() > 0 {
+		task.NotebookTask = &db.NotebookTask{}
+	}
+	if diff.Get("spark_jar_task").(*schema.Set).Len() > 0 {
+		task.SparkJarTask = &db.SparkJarTask{}
+	}
+	if diff.Get("spark_python_task").(*schema.Set).Len() > 0 {
+		task.SparkPythonTask = &db.SparkPythonTask{}
+	}
+	if diff.Get("spark_submit_task").(*schema.Set).Len() > 0 {
+		task.SparkSubmitTask = &db.SparkSubmitTask{}
+	}
+	if err := validateTaskKind(task); err != nil {
+		return fmt.Errorf("job run %s", err)
+	}
+
+	return nil
+}
+
+// setJobRun sets the state of a run, along with its notebook output once it
+// has finished.
+func setJobRun(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+	run *db.Run,
+) error {
+	data.Set("run_id", strconv.FormatInt(run.RunID, 10))
+	data.Set("run_page_url", run.RunPageURL)
+	if run.State != nil {
+		data.Set("life_cycle_state", string(run.State.LifeCycleState))
+		data.Set("state_message", run.State.StateMessage)
+		if run.State.ResultState != nil {
+			data.Set("result_state", string(*run.State.ResultState))
+		}
+	}
+
+	if !runIsTerminal(run) || data.Get("notebook_task").(*schema.Set).Len() == 0 {
+		return nil
+	}
+	output, err := client.(*providerMeta).client.Jobs().GetRunOutput(ctx, run.RunID)
+	if err != nil {
+		return err
+	}
+	if output.NotebookOutput != nil {
+		data.Set("notebook_output", output.NotebookOutput.Result)
+		data.Set("notebook_output_truncated", output.NotebookOutput.Truncated)
+	}
+
+	return nil
+}
@@ -4,18 +4,25 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	db "github.com/medivo/databricks-go"
 )
 
 func resourceJobs() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceJobsCreate,
-		Read:   resourceJobsRead,
-		Update: resourceJobsUpdate,
-		Delete: resourceJobsDelete,
+		Create:        resourceJobsCreate,
+		Read:          resourceJobsRead,
+		Update:        resourceJobsUpdate,
+		Delete:        resourceJobsDelete,
+		CustomizeDiff: resourceJobsCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"created_time": &schema.Schema{
 				Type:        schema.TypeString,
@@ -28,9 +35,10 @@ func resourceJobs() *schema.Resource {
 				Required:    true,
 			},
 			"cluster_id": &schema.Schema{
-				Type:        schema.TypeString,
-				Description: `Spark cluster id.`,
-				Required:    true,
+				Type:          schema.TypeString,
+				Description:   `Spark cluster id. Required unless the job is made of task blocks.`,
+				Optional:      true,
+				ConflictsWith: []string{"task"},
 			},
 			"timeout_seconds": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -61,12 +69,22 @@ func resourceJobs() *schema.Resource {
 			},
 			"run_id": &schema.Schema{
 				Type:        schema.TypeString,
-				Description: `Spark Job run id.`,
+				Description: `Spark Job run id of the most recent run.`,
 				Computed:    true,
 			},
 			"state": &schema.Schema{
 				Type:        schema.TypeString,
-				Description: `Job state.`,
+				Description: `Life cycle state of the most recent run.`,
+				Computed:    true,
+			},
+			"last_run_result": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Result state of the most recent run, empty while it is running.`,
+				Computed:    true,
+			},
+			"last_run_start_time": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Start time of the most recent run.`,
 				Computed:    true,
 			},
 			"creator": &schema.Schema{
@@ -78,144 +96,108 @@ func resourceJobs() *schema.Resource {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"jar": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: `If jar, URI of the jar to be installed. DBFS and S3 URIs are supported.`,
-						},
-						"egg": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: `If egg, URI of the egg to be installed. DBFS and S3 URIs are supported.`,
-						},
-						"whl": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: `If whl, URI of the wheel or zipped wheels to be installed. DBFS and S3 URIs are supported. For example: { "whl": "dbfs:/my/whl" } or { "whl": "s3://my-bucket/whl" }. If S3 is used, make sure the cluster has read access on the library. You may need to launch the cluster with an IAM role to access the S3 URI. Also the wheel file name needs to use the correct convention. If zipped wheels are to be installed, the file name suffix should be .wheelhouse.zip.`,
-						},
-						"pypi": {
-							Type:        schema.TypeSet,
-							Optional:    true,
-							Description: `If pypi, specification of a PyPi library to be installed.`,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"package": &schema.Schema{
-										Type:        schema.TypeString,
-										Required:    true,
-										Description: `The name of the PyPi package to install. An optional exact version specification is also supported. Examples: simplejson and simplejson==3.8.0.`,
-									},
-									"repo": &schema.Schema{
-										Type:        schema.TypeString,
-										Optional:    true,
-										Description: `The repository where the package can be found. If not specified, the default pip index is used.`,
-									},
-								},
-							},
-						},
-						"maven": {
-							Type:        schema.TypeSet,
-							Optional:    true,
-							Description: `If maven, specification of a Maven library to be installed.`,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"coordinates": &schema.Schema{
-										Type:        schema.TypeString,
-										Required:    true,
-										Description: `Gradle-style Maven coordinates. For example: org.jsoup:jsoup:1.7.2.`,
-									},
-									"repo": &schema.Schema{
-										Type:        schema.TypeString,
-										Optional:    true,
-										Description: `Maven repo to install the Maven package from. If omitted, both Maven Central Repository and Spark Packages are searched.`,
-									},
-									"exclusions": &schema.Schema{
-										Type:     schema.TypeList,
-										Optional: true,
-										Description: `List of dependences to exclude. For example: ["slf4j:slf4j", "*:hadoop-client"].
-
-`,
-										Elem: &schema.Schema{
-											Type: schema.TypeString,
-										},
-									},
-								},
-							},
-						},
-					},
+					Schema: librarySchema(),
 				},
 			},
 			"schedule": {
 				Type:     schema.TypeSet,
 				Optional: true,
+				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"quartz_cron_expression": &schema.Schema{
-							Type:        schema.TypeString,
-							Description: `A cron expression using quartz syntax that describes the schedule for a job.`,
-							Required:    true,
+							Type:         schema.TypeString,
+							Description:  `A cron expression using quartz syntax that describes the schedule for a job.`,
+							Required:     true,
+							ValidateFunc: validateQuartzCron,
 						},
 						"timezone_id": &schema.Schema{
+							Type:         schema.TypeString,
+							Description:  `A Java timezone id. The schedule for a job will be resolved with respect to this timezone.`,
+							Required:     true,
+							ValidateFunc: validateTimezone,
+						},
+						"pause_status": &schema.Schema{
 							Type:        schema.TypeString,
-							Description: `A Java timezone id. The schedule for a job will be resolved with respect to this timezone.`,
-							Required:    true,
+							Description: `Whether the schedule is paused, either PAUSED or UNPAUSED.`,
+							Optional:    true,
+							Default:     string(db.PauseStatusUnpaused),
+							ValidateFunc: validation.StringInSlice([]string{
+								string(db.PauseStatusPaused),
+								string(db.PauseStatusUnpaused),
+							}, false),
 						},
 					},
 				},
 			},
-			"notebook_task": {
-				Type:     schema.TypeSet,
+			"run_on_change": &schema.Schema{
+				Type: schema.TypeBool,
+				Description: `Run the job after it has been created or its
+				settings changed. When wait_for_run is set and the first run
+				of a new job fails, terraform taints the job and the next
+				apply replaces it, with a new job id and an empty run history.`,
 				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"notebook_path": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: `The absolute path of the notebook to be run in the Databricks Workspace. This path must begin with a slash.`,
-						},
-						"base_parameters": {
-							Type:     schema.TypeMap,
-							Optional: true,
-							Description: `Base parameters to be used for each run of this job. If the run is initiated by a call to run-now with parameters specified, the two parameters maps will be merged. If the same key is specified in base_parameters and in run-now, the value from run-now will be used.
-
-If the notebook takes a parameter that is not specified in the job’s base_parameters or the run-now override parameters, the default value from the notebook will be used.
-
-These parameters can be retrieved in a notebook by using dbutils.widgets.get().
-`,
-						},
-					},
+				Default:  false,
+			},
+			"wait_for_run": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Wait for the run started by run_on_change to finish, failing the apply unless it succeeds.`,
+				Optional:    true,
+				Default:     true,
+			},
+			"next_runs_count": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Number of upcoming runs of the schedule to list in next_runs.`,
+				Optional:    true,
+				Default:     5,
+			},
+			"next_runs": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `Run times of the schedule in its timezone following the last change of the schedule or next_runs_count, computed from quartz_cron_expression when planning that change. They are not refreshed as time passes, so earlier ones may already be in the past.`,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
 				},
 			},
-			"spark_python_task": {
+			"notebook_task":     notebookTaskSchema(),
+			"spark_python_task": sparkPythonTaskSchema(),
+			"spark_submit_task": sparkSubmitTaskSchema(),
+			"spark_jar_task":    sparkJarTaskSchema(),
+			"task": withConflicts(
+				jobTaskSchema(),
+				"cluster_id",
+				"libraries",
+				"notebook_task",
+				"spark_python_task",
+				"spark_submit_task",
+				"spark_jar_task",
+			),
+			"job_cluster": jobClusterSchema(),
+			"email_notifications": {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"python_file": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: `The absolute path of the notebook to be run in the Databricks Workspace. This path must begin with a slash.`,
+						"on_start": &schema.Schema{
+							Type:        schema.TypeList,
+							Description: `A list of email addresses to be notified when a run begins.`,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
 						},
-						"parameters": {
+						"on_success": &schema.Schema{
 							Type:        schema.TypeList,
+							Description: `A list of email addresses to be notified when a run successfully completes. A run is considered to have completed successfully if it ends with a TERMINATED life_cycle_state and a SUCCESSFUL result_state.`,
 							Optional:    true,
-							Description: `Command line parameters that will be passed to the Python file.`,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
 						},
-					},
-				},
-			},
-			"spark_submit_task": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"parameters": {
+						"on_failure": &schema.Schema{
 							Type:        schema.TypeList,
+							Description: `A list of email addresses to be notified when a run unsuccessfully completes. A run is considered to have completed unsuccessfully if it ends with an INTERNAL_ERROR life_cycle_state or a SKIPPED, FAILED, or TIMED_OUT result_state.`,
 							Optional:    true,
-							Description: `Command line parameters that will be passed to spark submit.`,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -223,37 +205,94 @@ These parameters can be retrieved in a notebook by using dbutils.widgets.get().
 					},
 				},
 			},
-			"spark_jar_task": {
-				Type:     schema.TypeSet,
-				Optional: true,
+			"run_as": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `The identity the job runs as, the creator of the job if unset.`,
+				Optional:    true,
+				MaxItems:    1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"main_class_name": {
-							Type:     schema.TypeString,
-							Required: true,
-							Description: `The full name of the class containing the main method to be executed. This class must be contained in a JAR provided as a library.
-
-The code should use SparkContext.getOrCreate to obtain a Spark context; otherwise, runs of the job will fail.`,
+						"user_name": &schema.Schema{
+							Type:          schema.TypeString,
+							Description:   `Email of an active workspace user. Conflicts with service_principal_name.`,
+							Optional:      true,
+							ConflictsWith: []string{"run_as.0.service_principal_name"},
 						},
-						"parameters": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Description: `Parameters that will be passed to the main method.`,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
+						"service_principal_name": &schema.Schema{
+							Type:          schema.TypeString,
+							Description:   `Application id of an active service principal. Conflicts with user_name.`,
+							Optional:      true,
+							ConflictsWith: []string{"run_as.0.user_name"},
 						},
 					},
 				},
 			},
-			"email_notifications": {
+			"parameter": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `Job-level parameters, passed to every task and overridable by run-now.`,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `Name of the parameter, unique within the job.`,
+							Required:    true,
+						},
+						"default": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `Value of the parameter when a run doesn't override it.`,
+							Required:    true,
+						},
+					},
+				},
+			},
+			"git_source": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: `A remote repository the notebooks of the job are taken from.`,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `URL of the repository.`,
+							Required:    true,
+						},
+						"provider": &schema.Schema{
+							Type:         schema.TypeString,
+							Description:  `Git provider hosting the repository, e.g. gitHub or gitLab.`,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(gitProviders, false),
+						},
+						"branch": &schema.Schema{
+							Type:          schema.TypeString,
+							Description:   `Branch to check out. Conflicts with tag and commit.`,
+							Optional:      true,
+							ConflictsWith: []string{"git_source.0.tag", "git_source.0.commit"},
+						},
+						"tag": &schema.Schema{
+							Type:          schema.TypeString,
+							Description:   `Tag to check out. Conflicts with branch and commit.`,
+							Optional:      true,
+							ConflictsWith: []string{"git_source.0.branch", "git_source.0.commit"},
+						},
+						"commit": &schema.Schema{
+							Type:          schema.TypeString,
+							Description:   `Commit hash to check out. Conflicts with branch and tag.`,
+							Optional:      true,
+							ConflictsWith: []string{"git_source.0.branch", "git_source.0.tag"},
+						},
+					},
+				},
+			},
+			"webhook_notifications": {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"on_start": &schema.Schema{
 							Type:        schema.TypeList,
-							Description: `A list of email addresses to be notified when a run begins.`,
+							Description: `A list of notification destination ids to be notified when a run begins.`,
 							Optional:    true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
@@ -261,7 +300,7 @@ The code should use SparkContext.getOrCreate to obtain a Spark context; otherwis
 						},
 						"on_success": &schema.Schema{
 							Type:        schema.TypeList,
-							Description: `A list of email addresses to be notified when a run successfully completes. A run is considered to have completed successfully if it ends with a TERMINATED life_cycle_state and a SUCCESSFUL result_state.`,
+							Description: `A list of notification destination ids to be notified when a run successfully completes.`,
 							Optional:    true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
@@ -269,7 +308,15 @@ The code should use SparkContext.getOrCreate to obtain a Spark context; otherwis
 						},
 						"on_failure": &schema.Schema{
 							Type:        schema.TypeList,
-							Description: `A list of email addresses to be notified when a run unsuccessfully completes. A run is considered to have completed unsuccessfully if it ends with an INTERNAL_ERROR life_cycle_state or a SKIPPED, FAILED, or TIMED_OUT result_state.`,
+							Description: `A list of notification destination ids to be notified when a run unsuccessfully completes.`,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"on_duration_warning_threshold_exceeded": &schema.Schema{
+							Type:        schema.TypeList,
+							Description: `A list of notification destination ids to be notified when a run takes longer than duration_warning_threshold_seconds.`,
 							Optional:    true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
@@ -278,26 +325,56 @@ The code should use SparkContext.getOrCreate to obtain a Spark context; otherwis
 					},
 				},
 			},
+			"duration_warning_threshold_seconds": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: `Duration in seconds after which a run triggers the on_duration_warning_threshold_exceeded notifications.`,
+				Optional:    true,
+			},
+			"no_alert_for_skipped_runs": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: `Don't send notifications for skipped runs.`,
+				Optional:    true,
+				Default:     false,
+			},
 		},
 	}
 }
 
 func resourceJobsCreate(data *schema.ResourceData, client interface{}) error {
-	jobsService := client.(*db.Client).Jobs()
+	jobsService := client.(*providerMeta).client.Jobs()
 	ctx := context.Background()
 
-	clusterID := data.Get("cluster_id").(string)
+	tasks, err := getJobTasks(data.Get("task").([]interface{}), client)
+	if err != nil {
+		return err
+	}
+	jobClusters, err := getJobClusters(data.Get("job_cluster").([]interface{}), client)
+	if err != nil {
+		return err
+	}
 
 	jobCreateReq := &db.JobCreateRequest{
-		ExistingClusterID:  &clusterID,
-		Name:               data.Get("name").(string),
-		NotebookTask:       getJobNotebookTask(data),
-		SparkJarTask:       getJobSparkJarTask(data),
-		SparkPythonTask:    getJobSparkPythonTask(data),
-		SparkSubmitTask:    getJobSparkSubmitTask(data),
-		Libraries:          getJobLibraries(data),
-		EmailNotifications: getJobEmailNotifications(data),
-		Schedule:           getJobCron(data),
+		Tasks:                tasks,
+		JobClusters:          jobClusters,
+		Name:                 data.Get("name").(string),
+		NotebookTask:         getNotebookTask(data.Get("notebook_task").(*schema.Set)),
+		SparkJarTask:         getSparkJarTask(data.Get("spark_jar_task").(*schema.Set)),
+		SparkPythonTask:      getSparkPythonTask(data.Get("spark_python_task").(*schema.Set)),
+		SparkSubmitTask:      getSparkSubmitTask(data.Get("spark_submit_task").(*schema.Set)),
+		Libraries:            getLibraries(data.Get("libraries").(*schema.Set)),
+		EmailNotifications:   getJobEmailNotifications(data),
+		WebhookNotifications: getJobWebhookNotifications(data),
+		Health:               getJobHealth(data),
+		Schedule:             getJobCron(data),
+		GitSource:            getJobGitSource(data),
+		RunAs:                getJobRunAs(data),
+		Parameters:           getJobParameters(data),
+		NotificationSettings: &db.JobNotificationSettings{
+			NoAlertForSkippedRuns: data.Get("no_alert_for_skipped_runs").(bool),
+		},
+	}
+	if clusterID := data.Get("cluster_id").(string); len(clusterID) > 0 {
+		jobCreateReq.ExistingClusterID = &clusterID
 	}
 
 	// going to lose some precision here, but what can you do?
@@ -337,7 +414,22 @@ func resourceJobsCreate(data *schema.ResourceData, client interface{}) error {
 	}
 
 	data.SetId(fmt.Sprintf("%d", id))
-	return nil
+	if err := setJobNextRuns(data); err != nil {
+		return err
+	}
+
+	err = runJobOnChange(
+		ctx,
+		data,
+		client,
+		id,
+		data.Timeout(schema.TimeoutCreate),
+	)
+	if err != nil {
+		return err
+	}
+
+	return setJobLastRun(ctx, data, client, id)
 }
 
 func resourceJobsRead(data *schema.ResourceData, client interface{}) error {
@@ -345,16 +437,36 @@ func resourceJobsRead(data *schema.ResourceData, client interface{}) error {
 	if err != nil {
 		return err
 	}
-	job, err := client.(*db.Client).Jobs().Get(context.Background(), jobID)
+	job, err := client.(*providerMeta).client.Jobs().Get(context.Background(), jobID)
 	if err != nil {
 		return err
 	}
 	data.Set("creator", job.CreatorUserName)
+	if job.Settings != nil {
+		if err := data.Set("git_source", flattenJobGitSource(job.Settings.GitSource)); err != nil {
+			return err
+		}
+		runAs := job.Settings.RunAs
+		if len(data.Get("run_as").([]interface{})) == 0 && runAs != nil &&
+			runAs.UserName == job.CreatorUserName {
+			// the API reports the creator of a job that has no run_as
+			runAs = nil
+		}
+		if err := data.Set("run_as", flattenJobRunAs(runAs)); err != nil {
+			return err
+		}
+		if err := data.Set("parameter", flattenJobParameters(job.Settings.Parameters)); err != nil {
+			return err
+		}
+	}
 	data.Set(
 		"created_time",
 		time.Unix(0, job.CreatedTime*1000).Format(time.RFC3339),
 	)
-	return nil
+	if err := setJobLastRun(context.Background(), data, client, jobID); err != nil {
+		return err
+	}
+	return setJobNextRuns(data)
 }
 
 func resourceJobsUpdate(data *schema.ResourceData, client interface{}) error {
@@ -362,16 +474,36 @@ func resourceJobsUpdate(data *schema.ResourceData, client interface{}) error {
 	if err != nil {
 		return err
 	}
-	clusterID := data.Get("cluster_id").(string)
+	tasks, err := getJobTasks(data.Get("task").([]interface{}), client)
+	if err != nil {
+		return err
+	}
+	jobClusters, err := getJobClusters(data.Get("job_cluster").([]interface{}), client)
+	if err != nil {
+		return err
+	}
+
 	settings := db.JobSettings{
-		ExistingClusterID:  &clusterID,
-		NotebookTask:       getJobNotebookTask(data),
-		SparkJarTask:       getJobSparkJarTask(data),
-		SparkPythonTask:    getJobSparkPythonTask(data),
-		SparkSubmitTask:    getJobSparkSubmitTask(data),
-		Libraries:          getJobLibraries(data),
-		EmailNotifications: getJobEmailNotifications(data),
-		Schedule:           getJobCron(data),
+		Tasks:                tasks,
+		JobClusters:          jobClusters,
+		NotebookTask:         getNotebookTask(data.Get("notebook_task").(*schema.Set)),
+		SparkJarTask:         getSparkJarTask(data.Get("spark_jar_task").(*schema.Set)),
+		SparkPythonTask:      getSparkPythonTask(data.Get("spark_python_task").(*schema.Set)),
+		SparkSubmitTask:      getSparkSubmitTask(data.Get("spark_submit_task").(*schema.Set)),
+		Libraries:            getLibraries(data.Get("libraries").(*schema.Set)),
+		EmailNotifications:   getJobEmailNotifications(data),
+		WebhookNotifications: getJobWebhookNotifications(data),
+		Health:               getJobHealth(data),
+		Schedule:             getJobCron(data),
+		GitSource:            getJobGitSource(data),
+		RunAs:                getJobUpdateRunAs(data),
+		Parameters:           getJobParameters(data),
+		NotificationSettings: &db.JobNotificationSettings{
+			NoAlertForSkippedRuns: data.Get("no_alert_for_skipped_runs").(bool),
+		},
+	}
+	if clusterID := data.Get("cluster_id").(string); len(clusterID) > 0 {
+		settings.ExistingClusterID = &clusterID
 	}
 
 	// going to lose some precision here, but what can you do?
@@ -405,11 +537,34 @@ func resourceJobsUpdate(data *schema.ResourceData, client interface{}) error {
 		settings.MaxRetries = &maxRuns
 	}
 
-	return client.(*db.Client).Jobs().Reset(
+	if err := setJobNextRuns(data); err != nil {
+		return err
+	}
+	if !jobSettingsChanged(data) {
+		return setJobLastRun(context.Background(), data, client, jobID)
+	}
+
+	err = client.(*providerMeta).client.Jobs().Reset(
 		context.Background(),
 		jobID,
 		settings,
 	)
+	if err != nil {
+		return err
+	}
+
+	err = runJobOnChange(
+		context.Background(),
+		data,
+		client,
+		jobID,
+		data.Timeout(schema.TimeoutUpdate),
+	)
+	if err != nil {
+		return err
+	}
+
+	return setJobLastRun(context.Background(), data, client, jobID)
 }
 
 func resourceJobsDelete(data *schema.ResourceData, client interface{}) error {
@@ -417,7 +572,299 @@ func resourceJobsDelete(data *schema.ResourceData, client interface{}) error {
 	if err != nil {
 		return err
 	}
-	return client.(*db.Client).Jobs().Delete(context.Background(), jobID)
+	return client.(*providerMeta).client.Jobs().Delete(context.Background(), jobID)
+}
+
+// jobLocalKeys are the attributes of a job that only change what the
+// provider does, not the settings of the job.
+var jobLocalKeys = map[string]bool{
+	"run_on_change":   true,
+	"wait_for_run":    true,
+	"next_runs_count": true,
+	"next_runs":       true,
+}
+
+// jobSettingsChanged reports whether any of the settings sent to the API
+// have changed.
+func jobSettingsChanged(data *schema.ResourceData) bool {
+	for key := range resourceJobs().Schema {
+		if !jobLocalKeys[key] && data.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// runJobOnChange starts a run of the job if run_on_change is set, and
+// waits for it to succeed if wait_for_run is set.
+func runJobOnChange(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+	jobID int64,
+	timeout time.Duration,
+) error {
+	if !data.Get("run_on_change").(bool) {
+		return nil
+	}
+
+	runID, err := client.(*providerMeta).client.Jobs().RunNow(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !data.Get("wait_for_run").(bool) {
+		return nil
+	}
+
+	run, err := waitForRun(ctx, client, runID, timeout)
+	if err != nil {
+		return err
+	}
+	return runError(run)
+}
+
+// setJobLastRun sets the attributes describing the most recent run of the
+// job, which are empty if the job never ran.
+func setJobLastRun(
+	ctx context.Context,
+	data *schema.ResourceData,
+	client interface{},
+	jobID int64,
+) error {
+	runsRes, err := client.(*providerMeta).client.Jobs().ListRuns(
+		ctx,
+		&db.RunsListRequest{
+			JobID: jobID,
+			Limit: 1,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	lastRun := map[string]interface{}{}
+	if len(runsRes.Runs) > 0 {
+		lastRun = flattenRun(runsRes.Runs[0])
+	}
+	attrs := map[string]string{
+		"run_id":              "run_id",
+		"state":               "life_cycle_state",
+		"last_run_result":     "result_state",
+		"last_run_start_time": "start_time",
+	}
+	for key, runKey := range attrs {
+		val, _ := lastRun[runKey].(string)
+		if err := data.Set(key, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resourceJobsCustomizeDiff checks the tasks of the job and shows the
+// upcoming runs of a changed schedule in the plan.
+func resourceJobsCustomizeDiff(diff *schema.ResourceDiff, client interface{}) error {
+	if err := validateJobTasks(diff); err != nil {
+		return err
+	}
+	if err := validateJobNewClusters(diff, client); err != nil {
+		return err
+	}
+	if err := validateJobGitSource(diff); err != nil {
+		return err
+	}
+	if err := validateJobRunAs(diff); err != nil {
+		return err
+	}
+	if err := validateJobParameters(diff); err != nil {
+		return err
+	}
+
+	if !diff.HasChange("schedule") && !diff.HasChange("next_runs_count") {
+		return nil
+	}
+	if !diff.NewValueKnown("schedule") {
+		return diff.SetNewComputed("next_runs")
+	}
+
+	nextRuns, err := jobNextRuns(
+		diff.Get("schedule").(*schema.Set),
+		diff.Get("next_runs_count").(int),
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	return diff.SetNew("next_runs", nextRuns)
+}
+
+// validateJobTasks checks that the job either runs on cluster_id or is made
+// of tasks whose dependencies and clusters exist, each running one kind of
+// task on one cluster.
+func validateJobTasks(diff *schema.ResourceDiff) error {
+	tasks := diff.Get("task").([]interface{})
+	jobClusters := diff.Get("job_cluster").([]interface{})
+	if len(tasks) == 0 {
+		if len(jobClusters) > 0 {
+			return fmt.Errorf("job_cluster can only be used by task blocks")
+		}
+		if len(diff.Get("cluster_id").(string)) == 0 && diff.NewValueKnown("cluster_id") {
+			return fmt.Errorf("one of cluster_id or task must be set")
+		}
+		return nil
+	}
+	if err := validateJobTaskGraph(tasks, jobClusters); err != nil {
+		return err
+	}
+
+	for i, taskData := range tasks {
+		prefix := fmt.Sprintf("task.%d.", i)
+		if !diff.NewValueKnown(prefix+"existing_cluster_id") ||
+			!diff.NewValueKnown(prefix+"job_cluster_key") {
+			// an unknown cluster may still turn out to be set
+			continue
+		}
+		task := jobTaskOutline(taskData.(map[string]interface{}))
+		if err := validateJobTask(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateJobNewClusters validates the new_cluster blocks of the tasks and
+// job clusters against the workspace.
+func validateJobNewClusters(diff *schema.ResourceDiff, client interface{}) error {
+	for _, key := range []string{"task", "job_cluster"} {
+		for i := range diff.Get(key).([]interface{}) {
+			prefix := fmt.Sprintf("%s.%d.", key, i)
+			if err := validateNewCluster(diff, client, prefix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateJobGitSource checks that a git_source checks out exactly one
+// ref, and that the notebooks of the job are relative to the repository.
+func validateJobGitSource(diff *schema.ResourceDiff) error {
+	gitSources := diff.Get("git_source").([]interface{})
+	if len(gitSources) == 0 || gitSources[0] == nil || !diff.NewValueKnown("git_source") {
+		return nil
+	}
+	gitSource := gitSources[0].(map[string]interface{})
+
+	refs := 0
+	for _, ref := range []string{"branch", "tag", "commit"} {
+		if !diff.NewValueKnown("git_source.0." + ref) {
+			return nil
+		}
+		if len(gitSource[ref].(string)) > 0 {
+			refs++
+		}
+	}
+	if refs != 1 {
+		return fmt.Errorf("git_source must set exactly one of branch, tag or commit")
+	}
+
+	notebookTasks := diff.Get("notebook_task").(*schema.Set).List()
+	for _, task := range diff.Get("task").([]interface{}) {
+		notebookTasks = append(
+			notebookTasks,
+			task.(map[string]interface{})["notebook_task"].(*schema.Set).List()...,
+		)
+	}
+	for _, notebookTask := range notebookTasks {
+		path := notebookTask.(map[string]interface{})["notebook_path"].(string)
+		if strings.HasPrefix(path, "/") {
+			return fmt.Errorf(
+				"notebook_path %q must be relative to the repository when git_source is set",
+				path,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateJobRunAs checks that run_as sets exactly one identity.
+func validateJobRunAs(diff *schema.ResourceDiff) error {
+	runAs := diff.Get("run_as").([]interface{})
+	if len(runAs) == 0 || runAs[0] == nil || !diff.NewValueKnown("run_as") {
+		return nil
+	}
+	runAsMap := runAs[0].(map[string]interface{})
+	if len(runAsMap["user_name"].(string)) == 0 &&
+		len(runAsMap["service_principal_name"].(string)) == 0 {
+		return fmt.Errorf("run_as must set one of user_name or service_principal_name")
+	}
+	return nil
+}
+
+// validateJobParameters checks that the names of the parameters are unique.
+func validateJobParameters(diff *schema.ResourceDiff) error {
+	names := map[string]bool{}
+	for _, param := range diff.Get("parameter").([]interface{}) {
+		name := param.(map[string]interface{})["name"].(string)
+		if names[name] {
+			return fmt.Errorf("parameter %q is defined more than once", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// setJobNextRuns computes next_runs if the plan did not, such as when the
+// schedule was unknown at plan time or the job was imported. Otherwise the
+// planned value is kept so that refreshing the job does not change it.
+func setJobNextRuns(data *schema.ResourceData) error {
+	if len(data.Get("next_runs").([]interface{})) > 0 {
+		return nil
+	}
+	nextRuns, err := jobNextRuns(
+		data.Get("schedule").(*schema.Set),
+		data.Get("next_runs_count").(int),
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	return data.Set("next_runs", nextRuns)
+}
+
+// jobNextRuns lists the next count times after from that a schedule fires.
+// Paused schedules never fire.
+func jobNextRuns(schedules *schema.Set, count int, from time.Time) ([]string, error) {
+	nextRuns := []string{}
+	for _, scheduleData := range schedules.List() {
+		scheduleMap := scheduleData.(map[string]interface{})
+		if scheduleMap["pause_status"].(string) == string(db.PauseStatusPaused) {
+			continue
+		}
+
+		cron, err := parseQuartzCron(scheduleMap["quartz_cron_expression"].(string))
+		if err != nil {
+			return nil, err
+		}
+		loc, err := time.LoadLocation(scheduleMap["timezone_id"].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		next := from.In(loc)
+		for len(nextRuns) < count {
+			var ok bool
+			next, ok = cron.next(next)
+			if !ok {
+				break
+			}
+			nextRuns = append(nextRuns, next.Format(time.RFC3339))
+		}
+	}
+
+	return nextRuns, nil
 }
 
 func getJobCron(data *schema.ResourceData) *db.CronSchedule {
@@ -429,10 +876,13 @@ func getJobCron(data *schema.ResourceData) *db.CronSchedule {
 	for _, cronData := range cronsData.List() {
 		cronMap := cronData.(map[string]interface{})
 		for cronOpt, cronOptData := range cronMap {
-			if cronOpt == "quartz_cron_expression" {
+			switch cronOpt {
+			case "quartz_cron_expression":
 				cronSchedule.QuartzCronExpression = cronOptData.(string)
-			} else {
+			case "timezone_id":
 				cronSchedule.TimezoneID = cronOptData.(string)
+			case "pause_status":
+				cronSchedule.PauseStatus = db.PauseStatus(cronOptData.(string))
 			}
 		}
 	}
@@ -468,199 +918,156 @@ func getJobEmailNotifications(data *schema.ResourceData) *db.JobEmailNotificatio
 	return emailNotifications
 }
 
-func getJobLibraries(data *schema.ResourceData) []db.Library {
-	libsData := data.Get("libraries").(*schema.Set)
-	libs := []db.Library{}
-	for _, libData := range libsData.List() {
-		lib := libData.(map[string]interface{})
-		library := db.Library{}
-		for libType, libTypeData := range lib {
-			switch libType {
-			case "jar":
-				jar := libTypeData.(string)
-				if len(jar) > 0 {
-					library.Jar = &jar
-				}
-			case "egg":
-				egg := libTypeData.(string)
-				if len(egg) > 0 {
-					library.Egg = &egg
-				}
-			case "whl":
-				whl := libTypeData.(string)
-				if len(whl) > 0 {
-					library.Whl = &whl
-				}
-			case "pypi":
-				pypiLibrary := db.PythonPyPiLibrary{}
-				pypiTfData := libTypeData.(*schema.Set)
-				for _, setData := range pypiTfData.List() {
-					setDataMap := setData.(map[string]interface{})
-					packageStr := setDataMap["package"].(string)
-					if len(packageStr) > 0 {
-						pypiLibrary.Package = packageStr
-					}
-					repo, ok := setDataMap["repo"]
-					if ok {
-						repoStr := repo.(string)
-						if len(repoStr) > 0 {
-							pypiLibrary.Repo = &repoStr
-						}
-					}
-				}
-				library.Pypi = &pypiLibrary
-			case "maven":
-				mavenLibrary := db.MavenLibrary{}
-				mavenTfData := libTypeData.(*schema.Set)
-				for _, setData := range mavenTfData.List() {
-					setDataMap := setData.(map[string]interface{})
-					coords := setDataMap["coordinates"].(string)
-					if len(coords) > 0 {
-						mavenLibrary.Coordinates = coords
-					}
-					repo, ok := setDataMap["repo"]
-					if ok {
-						repoStr := repo.(string)
-						if len(repoStr) > 0 {
-							mavenLibrary.Repo = &repoStr
-						}
-					}
-					// this is tedious....
-					exIfaces := setDataMap["exclusions"].([]interface{})
-					exclusions := make([]string, len(exIfaces))
-					for i, exIface := range exIfaces {
-						exclusions[i] = exIface.(string)
-					}
-					mavenLibrary.Exclusions = exclusions
-				}
-				library.Maven = &mavenLibrary
-			case "cran":
-				cranLibrary := db.RCranLibrary{}
-				cranTfData := libTypeData.(*schema.Set)
-				for _, setData := range cranTfData.List() {
-					setDataMap := setData.(map[string]interface{})
-					packageStr := setDataMap["package"].(string)
-					if len(packageStr) > 0 {
-						cranLibrary.Package = packageStr
-					}
-					repo, ok := setDataMap["repo"]
-					if ok {
-						repoStr := repo.(string)
-						if len(repoStr) > 0 {
-							cranLibrary.Repo = &repoStr
-						}
-					}
-				}
-				library.Cran = &cranLibrary
+func getJobWebhookNotifications(data *schema.ResourceData) *db.WebhookNotifications {
+	webhooksData := data.Get("webhook_notifications").(*schema.Set)
+	if webhooksData.Len() == 0 {
+		return nil
+	}
+	webhookNotifications := &db.WebhookNotifications{}
+	for _, webhookData := range webhooksData.List() {
+		listData := webhookData.(map[string]interface{})
+		for listType, listTypeData := range listData {
+			idsIface := listTypeData.([]interface{})
+			webhooks := make([]db.Webhook, len(idsIface))
+			for i, idIface := range idsIface {
+				webhooks[i] = db.Webhook{ID: idIface.(string)}
+			}
+			switch listType {
+			case "on_start":
+				webhookNotifications.OnStart = webhooks
+			case "on_success":
+				webhookNotifications.OnSuccess = webhooks
+			case "on_failure":
+				webhookNotifications.OnFailure = webhooks
+			case "on_duration_warning_threshold_exceeded":
+				webhookNotifications.OnDurationWarningThresholdExceeded = webhooks
 			}
 		}
-		libs = append(libs, library)
 	}
 
-	return libs
+	return webhookNotifications
 }
 
-func getJobNotebookTask(data *schema.ResourceData) *db.NotebookTask {
-	notebookIface, ok := data.GetOk("notebook_task")
+// getJobHealth returns the rule that flags runs taking longer than
+// duration_warning_threshold_seconds, if set.
+func getJobHealth(data *schema.ResourceData) *db.JobsHealthRules {
+	threshold, ok := data.GetOk("duration_warning_threshold_seconds")
 	if !ok {
 		return nil
 	}
-	notebookTask := &db.NotebookTask{}
-	noteSchema := notebookIface.(*schema.Set)
-
-	for _, noteData := range noteSchema.List() {
-		noteMap := noteData.(map[string]interface{})
-		if pathIface, ok := noteMap["notebook_path"]; ok {
-			notebookTask.NotebookPath = pathIface.(string)
-		}
-		if paramMap, ok := noteMap["base_parameters"]; ok {
-			params := []db.ParamPair{}
-			for key, val := range paramMap.(map[string]interface{}) {
-				params = append(params,
-					db.ParamPair{
-						Key:   key,
-						Value: val.(string),
-					},
-				)
-			}
-			notebookTask.BaseParameters = params
-		}
+	return &db.JobsHealthRules{
+		Rules: []db.JobsHealthRule{
+			{
+				Metric: db.JobsHealthMetricRunDurationSeconds,
+				Op:     db.JobsHealthOperatorGreaterThan,
+				Value:  int64(threshold.(int)),
+			},
+		},
 	}
+}
 
-	return notebookTask
+// gitProviders are the providers a git_source can be hosted by.
+var gitProviders = []string{
+	string(db.GitProviderGitHub),
+	string(db.GitProviderGitHubEnterprise),
+	string(db.GitProviderBitbucketCloud),
+	string(db.GitProviderBitbucketServer),
+	string(db.GitProviderGitLab),
+	string(db.GitProviderGitLabEnterpriseEdition),
+	string(db.GitProviderAzureDevOpsServices),
+	string(db.GitProviderAWSCodeCommit),
 }
 
-func getJobSparkJarTask(data *schema.ResourceData) *db.SparkJarTask {
-	taskIface, ok := data.GetOk("spark_jar_task")
-	if !ok {
+func getJobGitSource(data *schema.ResourceData) *db.GitSource {
+	gitSources := data.Get("git_source").([]interface{})
+	if len(gitSources) == 0 || gitSources[0] == nil {
 		return nil
 	}
-	jarTask := &db.SparkJarTask{}
+	gitSource := gitSources[0].(map[string]interface{})
 
-	taskSchema := taskIface.(*schema.Set)
-
-	for _, taskData := range taskSchema.List() {
-		taskMap := taskData.(map[string]interface{})
-		if mainIface, ok := taskMap["main_class_name"]; ok {
-			jarTask.MainClassName = mainIface.(string)
-		}
-		if paramSlice, ok := taskMap["parameters"]; ok {
-			params := make([]string, len(paramSlice.([]interface{})))
-			for i, val := range paramSlice.([]interface{}) {
-				params[i] = val.(string)
-			}
-			jarTask.Parameters = params
-		}
+	return &db.GitSource{
+		URL:      gitSource["url"].(string),
+		Provider: db.GitProvider(gitSource["provider"].(string)),
+		Branch:   gitSource["branch"].(string),
+		Tag:      gitSource["tag"].(string),
+		Commit:   gitSource["commit"].(string),
 	}
+}
 
-	return jarTask
+func flattenJobGitSource(gitSource *db.GitSource) []interface{} {
+	if gitSource == nil {
+		return []interface{}{}
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"url":      gitSource.URL,
+			"provider": string(gitSource.Provider),
+			"branch":   gitSource.Branch,
+			"tag":      gitSource.Tag,
+			"commit":   gitSource.Commit,
+		},
+	}
 }
 
-func getJobSparkPythonTask(data *schema.ResourceData) *db.SparkPythonTask {
-	taskIface, ok := data.GetOk("spark_python_task")
-	if !ok {
+func getJobRunAs(data *schema.ResourceData) *db.JobRunAs {
+	runAs := data.Get("run_as").([]interface{})
+	if len(runAs) == 0 || runAs[0] == nil {
 		return nil
 	}
-	pythonTask := &db.SparkPythonTask{}
+	runAsMap := runAs[0].(map[string]interface{})
 
-	taskSchema := taskIface.(*schema.Set)
+	return &db.JobRunAs{
+		UserName:             runAsMap["user_name"].(string),
+		ServicePrincipalName: runAsMap["service_principal_name"].(string),
+	}
+}
 
-	for _, taskData := range taskSchema.List() {
-		taskMap := taskData.(map[string]interface{})
-		if mainIface, ok := taskMap["python_file"]; ok {
-			pythonTask.PythonFile = mainIface.(string)
-		}
-		if paramSlice, ok := taskMap["parameters"]; ok {
-			params := make([]string, len(paramSlice.([]interface{})))
-			for i, val := range paramSlice.([]interface{}) {
-				params[i] = val.(string)
-			}
-			pythonTask.Parameters = params
-		}
+// getJobUpdateRunAs returns the run_as of an updated job. Leaving it out of
+// the settings keeps the identity the job runs as, so a job without run_as
+// is set back to run as its creator.
+func getJobUpdateRunAs(data *schema.ResourceData) *db.JobRunAs {
+	if runAs := getJobRunAs(data); runAs != nil {
+		return runAs
+	}
+	creator := data.Get("creator").(string)
+	if len(creator) == 0 {
+		return nil
 	}
+	return &db.JobRunAs{UserName: creator}
+}
 
-	return pythonTask
+func flattenJobRunAs(runAs *db.JobRunAs) []interface{} {
+	if runAs == nil {
+		return []interface{}{}
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"user_name":              runAs.UserName,
+			"service_principal_name": runAs.ServicePrincipalName,
+		},
+	}
 }
 
-func getJobSparkSubmitTask(data *schema.ResourceData) *db.SparkSubmitTask {
-	taskIface, ok := data.GetOk("spark_submit_task")
-	if !ok {
-		return nil
+func getJobParameters(data *schema.ResourceData) []db.JobParameterDefinition {
+	params := []db.JobParameterDefinition{}
+	for _, param := range data.Get("parameter").([]interface{}) {
+		paramMap := param.(map[string]interface{})
+		params = append(params, db.JobParameterDefinition{
+			Name:    paramMap["name"].(string),
+			Default: paramMap["default"].(string),
+		})
 	}
-	submitTask := &db.SparkSubmitTask{}
 
-	taskSchema := taskIface.(*schema.Set)
+	return params
+}
 
-	for _, taskData := range taskSchema.List() {
-		taskMap := taskData.(map[string]interface{})
-		if paramSlice, ok := taskMap["parameters"]; ok {
-			params := make([]string, len(paramSlice.([]interface{})))
-			for i, val := range paramSlice.([]interface{}) {
-				params[i] = val.(string)
-			}
-			submitTask.Parameters = params
+func flattenJobParameters(params []db.JobParameterDefinition) []interface{} {
+	paramsData := make([]interface{}, len(params))
+	for i, param := range params {
+		paramsData[i] = map[string]interface{}{
+			"name":    param.Name,
+			"default": param.Default,
 		}
 	}
-
-	return submitTask
+	return paramsData
 }
@@ -0,0 +1,221 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	db "github.com/medivo/databricks-go"
+)
+
+// notificationDestinationBlocks are the blocks of a notification
+// destination, each configuring one type of destination.
+var notificationDestinationBlocks = []string{
+	"slack",
+	"pagerduty",
+	"generic_webhook",
+}
+
+func resourceNotificationDestination() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceNotificationDestinationCreate,
+		Read:          resourceNotificationDestinationRead,
+		Update:        resourceNotificationDestinationUpdate,
+		Delete:        resourceNotificationDestinationDelete,
+		CustomizeDiff: resourceNotificationDestinationCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"display_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Name of the destination shown in the workspace.`,
+				Required:    true,
+			},
+			"destination_type": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: `Type of the destination, one of SLACK, PAGERDUTY or WEBHOOK.`,
+				Computed:    true,
+			},
+			"slack": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"pagerduty", "generic_webhook"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `Incoming webhook URL of the Slack channel.`,
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+			"pagerduty": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"slack", "generic_webhook"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"integration_key": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `Integration key of the PagerDuty service.`,
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+			"generic_webhook": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"slack", "pagerduty"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `URL the notifications are posted to.`,
+							Required:    true,
+							Sensitive:   true,
+						},
+						"username": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `User name for basic authentication.`,
+							Optional:    true,
+						},
+						"password": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: `Password for basic authentication.`,
+							Optional:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceNotificationDestinationCreate(data *schema.ResourceData, client interface{}) error {
+	config, err := getNotificationDestinationConfig(data)
+	if err != nil {
+		return err
+	}
+
+	id, err := client.(*providerMeta).client.NotificationDestinations().Create(
+		context.Background(),
+		&db.NotificationDestination{
+			DisplayName: data.Get("display_name").(string),
+			Config:      config,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(id)
+
+	return resourceNotificationDestinationRead(data, client)
+}
+
+func resourceNotificationDestinationRead(data *schema.ResourceData, client interface{}) error {
+	destination, err := client.(*providerMeta).client.NotificationDestinations().Get(
+		context.Background(),
+		data.Id(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// the urls, keys and passwords are never returned, so the configured
+	// blocks are kept as they are
+	data.Set("display_name", destination.DisplayName)
+	data.Set("destination_type", string(destination.DestinationType))
+
+	return nil
+}
+
+func resourceNotificationDestinationUpdate(data *schema.ResourceData, client interface{}) error {
+	config, err := getNotificationDestinationConfig(data)
+	if err != nil {
+		return err
+	}
+
+	err = client.(*providerMeta).client.NotificationDestinations().Update(
+		context.Background(),
+		&db.NotificationDestination{
+			ID:          data.Id(),
+			DisplayName: data.Get("display_name").(string),
+			Config:      config,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return resourceNotificationDestinationRead(data, client)
+}
+
+func resourceNotificationDestinationDelete(data *schema.ResourceData, client interface{}) error {
+	return client.(*providerMeta).client.NotificationDestinations().Delete(
+		context.Background(),
+		data.Id(),
+	)
+}
+
+// resourceNotificationDestinationCustomizeDiff checks that the destination
+// has a type and replaces it when its type changes, as the type of a
+// destination cannot be updated. ConflictsWith already rules out more than
+// one type.
+func resourceNotificationDestinationCustomizeDiff(diff *schema.ResourceDiff, client interface{}) error {
+	configured := false
+	for _, key := range notificationDestinationBlocks {
+		if len(diff.Get(key).([]interface{})) > 0 {
+			configured = true
+		}
+	}
+	if !configured {
+		return fmt.Errorf("one of slack, pagerduty or generic_webhook must be set")
+	}
+
+	if len(diff.Id()) == 0 {
+		return nil
+	}
+	for _, key := range notificationDestinationBlocks {
+		oldConfig, newConfig := diff.GetChange(key)
+		if len(oldConfig.([]interface{})) != len(newConfig.([]interface{})) {
+			return diff.ForceNew(key)
+		}
+	}
+	return nil
+}
+
+func getNotificationDestinationConfig(data *schema.ResourceData) (*db.NotificationDestinationConfig, error) {
+	config := &db.NotificationDestinationConfig{}
+	if slack := data.Get("slack").([]interface{}); len(slack) > 0 {
+		slackMap := slack[0].(map[string]interface{})
+		config.Slack = &db.SlackConfig{
+			URL: slackMap["url"].(string),
+		}
+		return config, nil
+	}
+	if pagerduty := data.Get("pagerduty").([]interface{}); len(pagerduty) > 0 {
+		pagerdutyMap := pagerduty[0].(map[string]interface{})
+		config.PagerDuty = &db.PagerDutyConfig{
+			IntegrationKey: pagerdutyMap["integration_key"].(string),
+		}
+		return config, nil
+	}
+	if webhook := data.Get("generic_webhook").([]interface{}); len(webhook) > 0 {
+		webhookMap := webhook[0].(map[string]interface{})
+		config.GenericWebhook = &db.GenericWebhookConfig{
+			URL:      webhookMap["url"].(string),
+			Username: webhookMap["username"].(string),
+			Password: webhookMap["password"].(string),
+		}
+		return config, nil
+	}
+
+	return nil, fmt.Errorf("one of slack, pagerduty or generic_webhook must be set")
+}
@@ -0,0 +1,157 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	// embeds the timezone database so timezone ids are validated the same
+	// way on every host
+	_ "time/tzdata"
+
+	db "github.com/medivo/databricks-go"
+)
+
+// workspaceCache holds the runtimes and node types supported by a workspace
+// so they are only listed once per provider instance.
+type workspaceCache struct {
+	mu            sync.Mutex
+	sparkVersions []string
+	nodeTypes     []string
+}
+
+func (c *workspaceCache) getSparkVersions(client *db.Client) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sparkVersions != nil {
+		return c.sparkVersions, nil
+	}
+	versions, err := client.Cluster().SparkVersions(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(versions))
+	for i, version := range versions {
+		keys[i] = version.Key
+	}
+	c.sparkVersions = keys
+
+	return c.sparkVersions, nil
+}
+
+func (c *workspaceCache) getNodeTypes(client *db.Client) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.nodeTypes != nil {
+		return c.nodeTypes, nil
+	}
+	nodeTypes, err := client.Cluster().ListNodeTypes(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(nodeTypes))
+	for i, nodeType := range nodeTypes {
+		ids[i] = nodeType.NodeTypeID
+	}
+	c.nodeTypes = ids
+
+	return c.nodeTypes, nil
+}
+
+// validateSupported returns an error suggesting the closest supported value
+// if val is not one of the supported values.
+func validateSupported(field, val string, supported []string) error {
+	closest := ""
+	closestDistance := -1
+	for _, s := range supported {
+		if s == val {
+			return nil
+		}
+		distance := levenshtein(val, s)
+		if closestDistance < 0 || distance < closestDistance {
+			closest = s
+			closestDistance = distance
+		}
+	}
+
+	if len(closest) == 0 {
+		return fmt.Errorf("%s %q is not supported by the workspace", field, val)
+	}
+	return fmt.Errorf(
+		"%s %q is not supported by the workspace, did you mean %q?",
+		field,
+		val,
+		closest,
+	)
+}
+
+// validateQuartzCron is a schema.SchemaValidateFunc for Quartz cron
+// expressions.
+func validateQuartzCron(i interface{}, s string) ([]string, []error) {
+	if _, err := parseQuartzCron(i.(string)); err != nil {
+		return []string{}, []error{fmt.Errorf(
+			"%s is not a valid Quartz cron expression: %s", s, err),
+		}
+	}
+	return []string{}, []error{}
+}
+
+// validateTimezone is a schema.SchemaValidateFunc for IANA timezone ids.
+// time.LoadLocation also accepts "" and "Local", which are not timezone ids.
+func validateTimezone(i interface{}, s string) ([]string, []error) {
+	tz := i.(string)
+	if tz == "" || tz == "Local" {
+		return []string{}, []error{fmt.Errorf(
+			"%s is not a valid timezone id: %q", s, tz),
+		}
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return []string{}, []error{fmt.Errorf(
+			"%s is not a valid timezone id: %s", s, err),
+		}
+	}
+	return []string{}, []error{}
+}
+
+// validateRFC3339 is a schema.SchemaValidateFunc for RFC3339 timestamps.
+func validateRFC3339(i interface{}, s string) ([]string, []error) {
+	if _, err := time.Parse(time.RFC3339, i.(string)); err != nil {
+		return []string{}, []error{fmt.Errorf(
+			"%s must be a RFC3339 timestamp: %s", s, err),
+		}
+	}
+	return []string{}, []error{}
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(
+				prev[j]+1,
+				minInt(curr[j-1]+1, prev[j-1]+cost),
+			)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}